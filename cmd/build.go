@@ -1,7 +1,8 @@
 package cmd
 
 import (
-	"log"
+	"crypto/ed25519"
+	"fmt"
 
 	docatl "github.com/docat-org/docatl/pkg"
 	"github.com/spf13/cobra"
@@ -17,10 +18,32 @@ The 'docatl build' command can be used to create a documentation artifact
 which can then be used with 'docatl push' to upload to a docat server.
 
 The documentation artifact is *just* a ZIP archive.
+Files matching the gitignore-style patterns in a '.docatlignore' file
+in the documentation directory are left out of the artifact.
 
 Example:
 
 	docatl build docs/
+
+Build the documentation artifact into a separate directory:
+
+	docatl build docs/ --project myproject --version 1.0.0 --output-dir dist/ --output-file 'docs_{project}_{commit}.zip'
+
+Build the documentation artifact with tags and labels, which are stored in its metadata:
+
+	docatl build docs/ --project myproject --version 1.0.0 --tag latest --label team=platform
+
+Build a signed documentation artifact:
+
+	docatl build docs/ --project myproject --version 1.0.0 --sign-key docatl.key
+
+Build the documentation artifact with the project and version taken from git:
+
+	docatl build docs/ --project-from git --version-from git
+
+Rebuild the documentation artifact whenever the documentation changes:
+
+	docatl build docs/ --watch
 `,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
@@ -29,25 +52,114 @@ Example:
 		cobra.CheckErr(err)
 		version, err := cmd.Flags().GetString("version")
 		cobra.CheckErr(err)
+		if derived := derivedProject(cmd, docsPath); derived != "" {
+			project = derived
+		}
+		if derived := derivedVersion(cmd, docsPath); derived != "" {
+			version = derived
+		}
+
+		tags, err := cmd.Flags().GetStringSlice("tag")
+		cobra.CheckErr(err)
+		watch, err := cmd.Flags().GetBool("watch")
+		cobra.CheckErr(err)
+		opts := buildOptions(cmd)
+		labels := buildLabels(cmd)
 
-		outputPath, err := docatl.Build(docsPath, docatl.BuildMetadata{
-			Host:    docat.Host,
-			Project: project,
-			Version: version,
-		})
-		if err != nil {
-			log.Fatalf("unable to build documentation: %s", err)
+		var artifacts builtArtifacts
+		build := func() error {
+			outputPath, err := docatl.Build(docsPath, newBuildMetadata(docsPath, project, version, tags, labels), opts)
+			if err != nil {
+				return fmt.Errorf("unable to build documentation: %w", err)
+			}
+			artifacts.add(outputPath)
+			logf("Successfully build documentation, stored at: %s", outputPath)
+			logf("Push documentation with: `docatl push %s`", outputPath)
+
+			printResult(result{
+				Host:     docat.Host,
+				Project:  project,
+				Version:  version,
+				Tags:     tags,
+				Artifact: outputPath,
+			})
+			return nil
+		}
+
+		if err = build(); err != nil {
+			fail(err)
+		}
+
+		if watch {
+			watchDocs(cmd, docsPath, &artifacts, build)
 		}
-		log.Printf("Successfully build documentation, stored at: %s", outputPath)
-		log.Printf("Push documentation with: `docatl push %s`", outputPath)
 	},
 }
 
+func addBuildFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("exclude", []string{}, "gitignore-style pattern of files to leave out of the artifact (repeatable)")
+	cmd.Flags().StringSlice("include", []string{}, "gitignore-style pattern of files to put into the artifact, all others are left out (repeatable)")
+	cmd.Flags().String("compression", docatl.CompressionBzip2, "compression of the artifact (store, deflate, bzip2 or zstd)")
+	cmd.Flags().Int("compression-level", 0, "compression level, 0 uses the default of the compression")
+	cmd.Flags().Bool("reproducible", false, "build byte-identical artifacts for identical documentation")
+	cmd.Flags().String("output-file", "", "path of the artifact, may contain the placeholders {project}, {version} and {commit}")
+	cmd.Flags().String("output-dir", "", "directory to write the artifact to")
+	cmd.Flags().StringToString("label", map[string]string{}, "free-form label stored in the artifact metadata as key=value (repeatable)")
+	cmd.Flags().String("sign-key", "", "PEM encoded ed25519 private key to sign the artifact with, see 'docatl keygen'")
+}
+
+func buildOptions(cmd *cobra.Command) docatl.BuildOptions {
+	exclude, err := cmd.Flags().GetStringSlice("exclude")
+	cobra.CheckErr(err)
+	include, err := cmd.Flags().GetStringSlice("include")
+	cobra.CheckErr(err)
+	compression, err := cmd.Flags().GetString("compression")
+	cobra.CheckErr(err)
+	compressionLevel, err := cmd.Flags().GetInt("compression-level")
+	cobra.CheckErr(err)
+	reproducible, err := cmd.Flags().GetBool("reproducible")
+	cobra.CheckErr(err)
+	output, err := cmd.Flags().GetString("output-file")
+	cobra.CheckErr(err)
+	outputDir, err := cmd.Flags().GetString("output-dir")
+	cobra.CheckErr(err)
+	signKeyPath, err := cmd.Flags().GetString("sign-key")
+	cobra.CheckErr(err)
+
+	var signingKey ed25519.PrivateKey
+	if signKeyPath != "" {
+		if signingKey, err = docatl.ReadSigningKey(signKeyPath); err != nil {
+			fail(err)
+		}
+	}
+
+	return docatl.BuildOptions{
+		Exclude:          exclude,
+		Include:          include,
+		Compression:      compression,
+		CompressionLevel: compressionLevel,
+		Reproducible:     reproducible,
+		Output:           output,
+		OutputDir:        outputDir,
+		SigningKey:       signingKey,
+	}
+}
+
+func buildLabels(cmd *cobra.Command) map[string]string {
+	labels, err := cmd.Flags().GetStringToString("label")
+	cobra.CheckErr(err)
+	return labels
+}
+
 func init() {
 	rootCmd.AddCommand(buildCmd)
 
 	buildCmd.Flags().StringP("project", "p", "", "the name of the docat project")
 	buildCmd.Flags().StringP("version", "v", "", "the version of this documentation")
+	buildCmd.Flags().StringSliceP("tag", "t", []string{}, "tag stored in the artifact metadata, applied when the artifact is pushed (repeatable)")
+	addDeriveFlags(buildCmd)
+	addBuildFlags(buildCmd)
+	addWatchFlags(buildCmd)
 
 	setupEnv(buildCmd)
 }
@@ -1,11 +1,9 @@
 package cmd
 
 import (
-	"log"
+	"fmt"
 
-	docatl "github.com/docat-org/docatl/pkg"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var claimCmd = &cobra.Command{
@@ -24,31 +22,36 @@ Claim a project:
 	Run: func(cmd *cobra.Command, args []string) {
 		project := args[0]
 
-		claim, err := docat.Claim(project)
+		claim, err := docat.ClaimContext(cmd.Context(), project)
 		if err != nil {
-			log.Fatal(err)
+			fail(err)
 		}
-		log.Printf("Successfully claimed project %s. Store and use the following token: %s", project, claim.Token)
+		logf("Successfully claimed project %s. Store and use the following token: %s", project, claim.Token)
 
 		writeToConfig, err := cmd.Flags().GetBool("write-to-config")
 		cobra.CheckErr(err)
 
 		if writeToConfig {
-			configPath := viper.ConfigFileUsed()
-			err = docatl.WriteConfig(configPath, docatl.Config{
-				Host:   docat.Host,
-				ApiKey: claim.Token,
-			})
+			store, err := credentialStore()
+			if err == nil {
+				err = store.Store(docat.Host, project, claim.Token)
+			}
 			if err != nil {
-				log.Fatalf("unable to write claim to config: %s", err)
+				fail(fmt.Errorf("unable to store claim token: %w", err))
 			}
-			log.Printf("Updated config at '%s' with claim token", configPath)
+			logf("Stored claim token for project %s in the %s credential store", project, credentialStoreName())
 		}
+
+		printResult(result{
+			Host:    docat.Host,
+			Project: project,
+			Token:   claim.Token,
+		})
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(claimCmd)
 
-	claimCmd.Flags().BoolP("write-to-config", "w", false, "write claim token to config file")
+	claimCmd.Flags().BoolP("write-to-config", "w", false, "store claim token in the credential store (the config file by default)")
 }
@@ -0,0 +1,342 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	docatl "github.com/docat-org/docatl/pkg"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// configResult is the structured outcome of the config commands.
+type configResult struct {
+	Path  string `json:"path,omitempty" yaml:"path,omitempty"`
+	Key   string `json:"key,omitempty" yaml:"key,omitempty"`
+	Value any    `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// profileResult is the structured outcome of the profile commands.
+type profileResult struct {
+	Name    string `json:"name" yaml:"name"`
+	Host    string `json:"host,omitempty" yaml:"host,omitempty"`
+	Current bool   `json:"current" yaml:"current"`
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the docatl config file",
+	Long: `Manage the docatl config file.
+
+The config file can hold profiles with the settings for different docat servers:
+
+	host: https://docat.company.io
+	profile: internal
+	profiles:
+	  internal:
+	    host: https://docat.internal.company.io
+	    api-key: blabla
+	  public:
+	    host: https://docs.company.io
+	    retries: 5
+
+The settings of the selected profile take precedence over the top level settings.
+A profile is selected with 'docatl config use-profile', --profile or DOCATL_PROFILE.
+
+Settings are addressed by their key, where the keys of nested settings
+are separated by dots, e.g. 'profiles.public.host'.
+`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get KEY",
+	Short: "Print a setting of the config file",
+	Long: `Print a setting of the config file.
+
+	docatl config get host
+	docatl config get profiles.public.host
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		key := args[0]
+		configPath := viper.ConfigFileUsed()
+
+		config, err := docatl.ReadConfig(configPath)
+		if err != nil {
+			fail(err)
+		}
+		value, ok, err := config.Get(key)
+		if err != nil {
+			fail(err)
+		}
+		if !ok {
+			fail(fmt.Errorf("setting '%s' is not set in config file '%s'", key, configPath))
+		}
+
+		if outputFormat != outputText {
+			printResult(configResult{Path: configPath, Key: key, Value: value})
+			return
+		}
+
+		switch value.(type) {
+		case map[string]any, []any:
+			doc, err := yaml.Marshal(value)
+			cobra.CheckErr(err)
+			fmt.Print(string(doc))
+		default:
+			fmt.Println(value)
+		}
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set KEY VALUE",
+	Short: "Change a setting in the config file",
+	Long: `Change a setting in the config file, keeping all other settings.
+
+The VALUE is parsed as YAML, so lists can be set as well:
+
+	docatl config set host https://docat.company.io
+	docatl config set profiles.public.tag "[latest, stable]"
+`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		key, value := args[0], args[1]
+
+		configPath := editConfig(func(config *docatl.Config) error {
+			return config.Set(key, value)
+		})
+		logf("Set %s in config at '%s'", key, configPath)
+
+		printResult(configResult{Path: configPath, Key: key})
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset KEY",
+	Short: "Remove a setting from the config file",
+	Long: `Remove a setting from the config file, keeping all other settings.
+
+	docatl config unset api-key
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		key := args[0]
+
+		configPath := editConfig(func(config *docatl.Config) error {
+			ok, err := config.Unset(key)
+			if err == nil && !ok {
+				err = fmt.Errorf("setting '%s' is not set", key)
+			}
+			return err
+		})
+		logf("Removed %s from config at '%s'", key, configPath)
+
+		printResult(configResult{Path: configPath, Key: key})
+	},
+}
+
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Print the config file",
+	Long: `Print the config file, with api keys and other secrets masked.
+
+	docatl config view
+	docatl config view --show-secrets
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		showSecrets, err := cmd.Flags().GetBool("show-secrets")
+		cobra.CheckErr(err)
+
+		config, err := docatl.ReadConfig(viper.ConfigFileUsed())
+		if err != nil {
+			fail(err)
+		}
+		if !showSecrets {
+			if config, err = config.Masked(); err != nil {
+				fail(err)
+			}
+		}
+
+		doc, err := yaml.Marshal(config)
+		cobra.CheckErr(err)
+		if outputFormat == outputText {
+			fmt.Print(string(doc))
+			return
+		}
+
+		// decode the config into a map to print it with the keys of the config file
+		var settings map[string]any
+		cobra.CheckErr(yaml.Unmarshal(doc, &settings))
+		printResult(settings)
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the config file",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		configPath := viper.ConfigFileUsed()
+		if outputFormat == outputText {
+			fmt.Println(configPath)
+			return
+		}
+		printResult(configResult{Path: configPath})
+	},
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a config file",
+	Long: `Create a config file with the given host and api key.
+
+	docatl config init --host https://docat.company.io --api-key blabla
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		force, err := cmd.Flags().GetBool("force")
+		cobra.CheckErr(err)
+
+		configPath := viper.ConfigFileUsed()
+		if _, err = os.Stat(configPath); err == nil && !force {
+			fail(fmt.Errorf("config file '%s' already exists, use --force to overwrite it", configPath))
+		}
+
+		err = docatl.WriteConfig(configPath, docatl.Config{
+			Host:   docat.Host,
+			ApiKey: docat.ApiKey,
+		})
+		if err != nil {
+			fail(err)
+		}
+		logf("Created config at '%s'", configPath)
+
+		printResult(configResult{Path: configPath})
+	},
+}
+
+// editConfig applies the change to the config file and returns its path.
+func editConfig(edit func(config *docatl.Config) error) string {
+	configPath := viper.ConfigFileUsed()
+
+	config, err := docatl.ReadConfig(configPath)
+	if err != nil {
+		fail(err)
+	}
+	if err = edit(&config); err != nil {
+		fail(err)
+	}
+	if err = config.Validate(); err != nil {
+		fail(fmt.Errorf("unable to change config '%s' because %w, use 'docatl config use-profile' to select another profile", configPath, err))
+	}
+	if err = docatl.WriteConfig(configPath, config); err != nil {
+		fail(err)
+	}
+
+	return configPath
+}
+
+var useProfileCmd = &cobra.Command{
+	Use:   "use-profile PROFILE",
+	Short: "Select the profile used by default",
+	Long: `Select the profile used by default.
+
+Use the settings of the 'internal' profile from now on:
+
+	docatl config use-profile internal
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		configPath := viper.ConfigFileUsed()
+
+		config, err := docatl.ReadConfig(configPath)
+		if err != nil {
+			fail(err)
+		}
+		selected, ok := config.Profiles[name]
+		if !ok {
+			fail(fmt.Errorf("profile '%s' does not exist in config file '%s'", name, configPath))
+		}
+
+		config.Profile = name
+		if err = docatl.WriteConfig(configPath, config); err != nil {
+			fail(err)
+		}
+		logf("Switched to profile %s in config at '%s'", name, configPath)
+
+		printResult(profileResult{
+			Name:    name,
+			Host:    selected.Host,
+			Current: true,
+		})
+	},
+}
+
+var listProfilesCmd = &cobra.Command{
+	Use:   "list-profiles",
+	Short: "List the profiles of the config file",
+	Long: `List the profiles of the config file.
+
+The profile currently used is marked with '*'.
+
+	docatl config list-profiles
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := docatl.ReadConfig(viper.ConfigFileUsed())
+		if err != nil {
+			fail(err)
+		}
+
+		current := viper.GetString("profile")
+		profiles := []profileResult{}
+		for name, profile := range config.Profiles {
+			profiles = append(profiles, profileResult{
+				Name:    name,
+				Host:    profile.Host,
+				Current: name == current,
+			})
+		}
+		slices.SortFunc(profiles, func(a, b profileResult) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+
+		if outputFormat != outputText {
+			printResult(profiles)
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "CURRENT\tPROFILE\tHOST")
+		for _, profile := range profiles {
+			marker := ""
+			if profile.Current {
+				marker = "*"
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", marker, profile.Name, profile.Host)
+		}
+		_ = w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configViewCmd)
+	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(useProfileCmd)
+	configCmd.AddCommand(listProfilesCmd)
+
+	configViewCmd.Flags().Bool("show-secrets", false, "print api keys and other secrets unmasked")
+	configInitCmd.Flags().BoolP("force", "f", false, "overwrite an existing config file")
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	docatl "github.com/docat-org/docatl/pkg"
+	"github.com/spf13/viper"
+)
+
+// credentialStore returns the credential store selected with the `credentials` settings of the config.
+// The passphrase of the encrypted file is read from DOCATL_CREDENTIALS_PASSPHRASE.
+func credentialStore() (docatl.CredentialStore, error) {
+	switch store := viper.GetString("credentials.store"); store {
+	case "", docatl.CredentialStoreFile:
+		return docatl.FileCredentialStore{
+			ConfigPath: viper.ConfigFileUsed(),
+			Profile:    viper.GetString("profile"),
+		}, nil
+	case docatl.CredentialStoreEncryptedFile:
+		path := viper.GetString("credentials.path")
+		if path == "" {
+			var err error
+			if path, err = docatl.DefaultEncryptedCredentialsPath(); err != nil {
+				return nil, err
+			}
+		}
+		return docatl.EncryptedFileCredentialStore{
+			Path:       path,
+			Passphrase: os.Getenv(envPrefix + "_CREDENTIALS_PASSPHRASE"),
+		}, nil
+	case docatl.CredentialStoreHelper:
+		return docatl.HelperCredentialStore{Command: viper.GetString("credentials.helper")}, nil
+	default:
+		return nil, fmt.Errorf("unknown credential store '%s', must be one of: %s, %s, %s", store, docatl.CredentialStoreFile, docatl.CredentialStoreEncryptedFile, docatl.CredentialStoreHelper)
+	}
+}
+
+func credentialStoreName() string {
+	if store := viper.GetString("credentials.store"); store != "" {
+		return store
+	}
+	return docatl.CredentialStoreFile
+}
+
+// resolveApiKey sets the api key for the project targeted by the command, see apiKeyFor.
+func resolveApiKey(project string) {
+	docat.ApiKey = apiKeyFor(docat.Host, project, docat.ApiKey)
+}
+
+// apiKeyFor returns the api key for the project on the docat host.
+// An api key given with --api-key or DOCATL_API_KEY takes precedence over the api key of the project
+// in the credential store, which takes precedence over the api key for all projects.
+func apiKeyFor(host string, project string, apiKey string) string {
+	if explicitApiKey {
+		return apiKey
+	}
+
+	store, err := credentialStore()
+	if err != nil {
+		logf("unable to look up api key: %s", err)
+		return apiKey
+	}
+
+	lookup := func(project string) (string, bool) {
+		stored, ok, err := store.Get(host, project)
+		if err != nil {
+			logf("unable to look up api key: %s", err)
+		}
+		return stored, ok
+	}
+
+	if project != "" {
+		if stored, ok := lookup(project); ok {
+			return stored
+		}
+	}
+	if apiKey == "" {
+		if stored, ok := lookup(""); ok {
+			return stored
+		}
+	}
+	return apiKey
+}
@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"log"
-
 	"github.com/spf13/cobra"
 )
 
@@ -18,15 +16,22 @@ Delete documentation:
 	Args: cobra.ExactArgs(2),
 	PreRun: func(cmd *cobra.Command, args []string) {
 		ensureHost()
+		resolveApiKey(args[0])
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		project, version := args[0], args[1]
 
-		err := docat.Delete(project, version)
+		err := docat.DeleteContext(cmd.Context(), project, version)
 		if err != nil {
-			log.Fatal(err)
+			fail(err)
 		}
-		log.Printf("Successfully deleted version %s of project %s", version, project)
+		logf("Successfully deleted version %s of project %s", version, project)
+
+		printResult(result{
+			Host:    docat.Host,
+			Project: project,
+			Version: version,
+		})
 	},
 }
 
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+
+	util "github.com/docat-org/docatl/internal"
+	docatl "github.com/docat-org/docatl/pkg"
+	"github.com/spf13/cobra"
+)
+
+const sourceGit = "git"
+
+func addDeriveFlags(cmd *cobra.Command) {
+	cmd.Flags().String("project-from", "", "derive the project from 'git' (the name of the origin remote or repository directory)")
+	cmd.Flags().String("version-from", "", "derive the version from 'git' (the nearest tag or the short commit for untagged builds)")
+}
+
+// derivedProject returns the project derived from the repository containing dir, if --project-from is given.
+func derivedProject(cmd *cobra.Command, dir string) string {
+	return derive(cmd, "project-from", dir, util.GitProjectName)
+}
+
+// derivedVersion returns the version derived from the repository containing dir, if --version-from is given.
+func derivedVersion(cmd *cobra.Command, dir string) string {
+	return derive(cmd, "version-from", dir, util.GitDescribe)
+}
+
+func derive(cmd *cobra.Command, flag string, dir string, fromGit func(dir string) (string, error)) string {
+	source, err := cmd.Flags().GetString(flag)
+	cobra.CheckErr(err)
+
+	switch source {
+	case "":
+		return ""
+	case sourceGit:
+		value, err := fromGit(dir)
+		if err != nil {
+			fail(fmt.Errorf("unable to derive --%s %s: %w", flag, source, err))
+		}
+		return value
+	default:
+		fail(fmt.Errorf("unknown source '%s' for --%s, must be %s", source, flag, sourceGit))
+		return ""
+	}
+}
+
+// newBuildMetadata returns the metadata for an artifact built from the documentation directory,
+// including the git revision if the directory is part of a git repository.
+func newBuildMetadata(docsPath string, project string, version string, tags []string, labels map[string]string) docatl.BuildMetadata {
+	meta := docatl.BuildMetadata{
+		Host:    docat.Host,
+		Project: project,
+		Version: version,
+		Tags:    tags,
+		Labels:  labels,
+	}
+	if revision, err := util.GitHeadRevision(docsPath); err == nil {
+		meta.Commit = revision.Commit
+		meta.Branch = revision.Branch
+		meta.Dirty = revision.Dirty
+	}
+	return meta
+}
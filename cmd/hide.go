@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"log"
-
 	"github.com/spf13/cobra"
 )
 
@@ -18,17 +16,26 @@ Hide a project version:
 	Args: cobra.ExactArgs(2),
 	PreRun: func(cmd *cobra.Command, args []string) {
 		ensureHost()
+		resolveApiKey(args[0])
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		project, version := args[0], args[1]
 
-		err := docat.HideOrShowVersion(project, version, true)
+		err := docat.HideOrShowVersionContext(cmd.Context(), project, version, true)
 
 		if err != nil {
-			log.Fatal(err)
+			fail(err)
 		}
 
-		log.Printf("Successfully hid version %s of project %s", version, project)
+		logf("Successfully hid version %s of project %s", version, project)
+
+		hidden := true
+		printResult(result{
+			Host:    docat.Host,
+			Project: project,
+			Version: version,
+			Hidden:  &hidden,
+		})
 	},
 }
 
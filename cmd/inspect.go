@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	docatl "github.com/docat-org/docatl/pkg"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// inspectResult is the structured outcome of the inspect command.
+type inspectResult struct {
+	Metadata docatl.BuildMetadata  `json:"metadata" yaml:"metadata"`
+	Files    []docatl.ArtifactFile `json:"files" yaml:"files"`
+}
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect ARTIFACT",
+	Short: "Print the metadata and files of a documentation artifact",
+	Long: `Print the metadata and files of a documentation artifact.
+
+The metadata holds the project, version, tags and labels of the documentation
+as well as the git revision, build time and docatl version it was built with.
+
+	docatl inspect docs_myproject_1.0.0.zip
+	docatl inspect docs_myproject_1.0.0.zip --output json
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		artifactPath := args[0]
+
+		meta, err := docatl.ExtractMetadata(artifactPath)
+		if err != nil {
+			fail(err)
+		}
+		files, err := docatl.ListArtifactFiles(artifactPath)
+		if err != nil {
+			fail(err)
+		}
+
+		if outputFormat != outputText {
+			printResult(inspectResult{Metadata: meta, Files: files})
+			return
+		}
+
+		doc, err := yaml.Marshal(meta)
+		cobra.CheckErr(err)
+		fmt.Print(string(doc))
+		fmt.Println()
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "FILE\tSIZE\tCOMPRESSED\tMODIFIED")
+		for _, file := range files {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", file.Name, formatBytes(int64(file.Size)), formatBytes(int64(file.CompressedSize)), file.Modified.Format("2006-01-02 15:04:05"))
+		}
+		_ = w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(inspectCmd)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	docatl "github.com/docat-org/docatl/pkg"
+	"github.com/spf13/cobra"
+)
+
+// keygenResult is the structured outcome of the keygen command.
+type keygenResult struct {
+	PrivateKey string `json:"private-key" yaml:"private-key"`
+	PublicKey  string `json:"public-key" yaml:"public-key"`
+}
+
+var keygenCmd = &cobra.Command{
+	Use:   "keygen [NAME]",
+	Short: "Generate a key pair to sign documentation artifacts",
+	Long: `Generate an ed25519 key pair to sign documentation artifacts.
+
+The private key is written to NAME.key and the public key to NAME.pub,
+NAME defaults to 'docatl'. Keep the private key secret and use it with
+'docatl build --sign-key', and verify artifacts with the public key
+using 'docatl verify --public-key' or 'docatl push --require-signature --public-key'.
+
+	docatl keygen
+	docatl keygen release
+`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := "docatl"
+		if len(args) == 1 {
+			name = args[0]
+		}
+		force, err := cmd.Flags().GetBool("force")
+		cobra.CheckErr(err)
+
+		privateKeyPath, publicKeyPath := name+".key", name+".pub"
+		for _, path := range []string{privateKeyPath, publicKeyPath} {
+			if _, err = os.Stat(path); err == nil && !force {
+				fail(fmt.Errorf("key '%s' already exists, use --force to overwrite it", path))
+			}
+		}
+
+		if err = docatl.GenerateSigningKeys(privateKeyPath, publicKeyPath); err != nil {
+			fail(err)
+		}
+		logf("Generated private key %s and public key %s", privateKeyPath, publicKeyPath)
+
+		printResult(keygenResult{PrivateKey: privateKeyPath, PublicKey: publicKeyPath})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(keygenCmd)
+
+	keygenCmd.Flags().BoolP("force", "f", false, "overwrite existing keys")
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	docatl "github.com/docat-org/docatl/pkg"
+	"github.com/spf13/cobra"
+)
+
+// lintResult is the structured outcome of the lint command.
+type lintResult struct {
+	Docs   string             `json:"docs" yaml:"docs"`
+	Issues []docatl.LintIssue `json:"issues" yaml:"issues"`
+	Passed bool               `json:"passed" yaml:"passed"`
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint DOCS",
+	Short: "Check a documentation directory for problems before pushing it",
+	Long: `Check a documentation directory for problems before pushing it.
+
+Errors are reported for a missing index.html in the root, disallowed file types,
+symlinks pointing outside of the documentation and documentation exceeding --max-total-size.
+Warnings are reported for empty directories, files exceeding --max-file-size and
+links to absolute paths, which break when served by docat. Lint fails on errors,
+and with --strict on warnings as well. The size limits are only checked when given.
+
+'docatl push' lints documentation directories before building them, unless --no-lint is given.
+
+	docatl lint docs/
+	docatl lint docs/ --strict --max-file-size 5MiB --disallow .exe,.zip
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		docsPath := args[0]
+		rules := lintRules(cmd)
+		rules.Exclude, rules.Include = lintPatterns(cmd)
+		strict, err := cmd.Flags().GetBool("strict")
+		cobra.CheckErr(err)
+
+		issues, err := docatl.Lint(docsPath, rules)
+		if err != nil {
+			fail(err)
+		}
+		failed := lintFailed(issues, strict)
+
+		if outputFormat != outputText {
+			printResult(lintResult{Docs: docsPath, Issues: issues, Passed: !failed})
+		} else {
+			for _, issue := range issues {
+				fmt.Println(issue)
+			}
+		}
+
+		if failed {
+			fail(lintError(docsPath, issues, strict))
+		}
+		if len(issues) == 0 {
+			logf("No problems found in documentation %s", docsPath)
+		}
+	},
+}
+
+func addLintFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("strict", false, "fail on lint warnings as well")
+	cmd.Flags().String("max-file-size", "0", "size above which a file is reported, e.g. 25MiB, 0 disables the check")
+	cmd.Flags().String("max-total-size", "0", "size above which the documentation fails linting, e.g. 1GiB, 0 disables the check")
+	cmd.Flags().StringSlice("disallow", []string{".exe", ".dll", ".so", ".dylib", ".pem", ".key", ".env"}, "file extension which must not be part of the documentation (repeatable)")
+}
+
+func lintRules(cmd *cobra.Command) docatl.LintRules {
+	maxFileSize, err := cmd.Flags().GetString("max-file-size")
+	cobra.CheckErr(err)
+	maxTotalSize, err := cmd.Flags().GetString("max-total-size")
+	cobra.CheckErr(err)
+	disallow, err := cmd.Flags().GetStringSlice("disallow")
+	cobra.CheckErr(err)
+
+	rules := docatl.LintRules{}
+	if rules.MaxFileSize, err = parseSize(maxFileSize); err != nil {
+		fail(fmt.Errorf("invalid --max-file-size: %w", err))
+	}
+	if rules.MaxTotalSize, err = parseSize(maxTotalSize); err != nil {
+		fail(fmt.Errorf("invalid --max-total-size: %w", err))
+	}
+	for _, ext := range disallow {
+		if ext == "" {
+			continue
+		}
+		rules.DisallowedExtensions = append(rules.DisallowedExtensions, "."+strings.TrimPrefix(strings.ToLower(ext), "."))
+	}
+	return rules
+}
+
+// lintPatterns returns the exclude and include patterns of the lint command.
+func lintPatterns(cmd *cobra.Command) ([]string, []string) {
+	exclude, err := cmd.Flags().GetStringSlice("exclude")
+	cobra.CheckErr(err)
+	include, err := cmd.Flags().GetStringSlice("include")
+	cobra.CheckErr(err)
+	return exclude, include
+}
+
+// lintDocs lints the documentation directory before it is pushed, logging all issues.
+func lintDocs(docsPath string, rules docatl.LintRules, strict bool) error {
+	issues, err := docatl.Lint(docsPath, rules)
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		logf("%s", issue)
+	}
+	if lintFailed(issues, strict) {
+		return fmt.Errorf("refusing to push: %w, use --no-lint to push anyway", lintError(docsPath, issues, strict))
+	}
+	return nil
+}
+
+func lintFailed(issues []docatl.LintIssue, strict bool) bool {
+	for _, issue := range issues {
+		if issue.Severity == docatl.LintError || strict {
+			return true
+		}
+	}
+	return false
+}
+
+func lintError(docsPath string, issues []docatl.LintIssue, strict bool) error {
+	errs, warnings := 0, 0
+	for _, issue := range issues {
+		if issue.Severity == docatl.LintError {
+			errs++
+		} else {
+			warnings++
+		}
+	}
+	if strict {
+		return fmt.Errorf("documentation %s failed linting with %d errors and %d warnings", docsPath, errs, warnings)
+	}
+	return fmt.Errorf("documentation %s failed linting with %d errors", docsPath, errs)
+}
+
+// parseSize parses a size in bytes with an optional binary unit, e.g. 512KiB, 25MiB or 1GiB.
+func parseSize(size string) (int64, error) {
+	units := []struct {
+		suffix string
+		factor int64
+	}{
+		{"KiB", 1 << 10},
+		{"MiB", 1 << 20},
+		{"GiB", 1 << 30},
+		{"B", 1},
+	}
+
+	value, factor := strings.TrimSpace(size), int64(1)
+	for _, unit := range units {
+		if number, ok := strings.CutSuffix(value, unit.suffix); ok {
+			value, factor = strings.TrimSpace(number), unit.factor
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("'%s' is not a size like 512KiB, 25MiB or 1GiB", size)
+	}
+	return n * factor, nil
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().StringSlice("exclude", []string{}, "gitignore-style pattern of files to leave out of the check (repeatable)")
+	lintCmd.Flags().StringSlice("include", []string{}, "gitignore-style pattern of files to check, all others are left out (repeatable)")
+	addLintFlags(lintCmd)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	docatl "github.com/docat-org/docatl/pkg"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list [PROJECT]",
+	Short: "List projects and versions on a docat server",
+	Long: `List projects and versions on a docat server.
+
+List all projects and their versions:
+
+	docatl list
+
+List the versions of a single project, including hidden ones:
+
+	docatl list myproject --all
+`,
+	Args: cobra.MaximumNArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		ensureHost()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		includeHidden, err := cmd.Flags().GetBool("all")
+		cobra.CheckErr(err)
+
+		var projects []docatl.Project
+		if len(args) == 1 {
+			project, err := docat.GetProjectContext(cmd.Context(), args[0], includeHidden)
+			if err != nil {
+				fail(err)
+			}
+			projects = []docatl.Project{project}
+		} else {
+			projects, err = docat.ListProjectsContext(cmd.Context(), includeHidden)
+			if err != nil {
+				fail(err)
+			}
+		}
+
+		if outputFormat == outputText {
+			printProjects(projects)
+		} else {
+			printResult(projects)
+		}
+	},
+}
+
+func printProjects(projects []docatl.Project) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "PROJECT\tVERSION\tTAGS\tHIDDEN\tTIMESTAMP")
+	for _, project := range projects {
+		for _, version := range project.Versions {
+			timestamp := ""
+			if !version.Timestamp.IsZero() {
+				timestamp = version.Timestamp.Format(time.DateTime)
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", project.Name, version.Name, strings.Join(version.Tags, ","), version.Hidden, timestamp)
+		}
+	}
+	_ = w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().BoolP("all", "a", false, "include hidden versions")
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"text/tabwriter"
+
+	util "github.com/docat-org/docatl/internal"
+	docatl "github.com/docat-org/docatl/pkg"
+)
+
+type manifestResult struct {
+	Host    string                `json:"host" yaml:"host"`
+	Entries []manifestEntryResult `json:"entries" yaml:"entries"`
+	Failed  int                   `json:"failed" yaml:"failed"`
+}
+
+type manifestEntryResult struct {
+	Docs     string   `json:"docs" yaml:"docs"`
+	Project  string   `json:"project,omitempty" yaml:"project,omitempty"`
+	Version  string   `json:"version,omitempty" yaml:"version,omitempty"`
+	Tags     []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Artifact string   `json:"artifact,omitempty" yaml:"artifact,omitempty"`
+	Icon     string   `json:"icon,omitempty" yaml:"icon,omitempty"`
+	Hidden   bool     `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+	Error    string   `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// manifestOptions holds the settings of push which apply to every manifest entry.
+type manifestOptions struct {
+	build  docatl.BuildOptions
+	labels map[string]string
+	force  bool
+	// lint lints a documentation directory before it is built.
+	lint func(docsPath string) error
+	// verify verifies an artifact before it is pushed.
+	verify func(artifactPath string, built bool) error
+	// removeBuilt removes an artifact built from a documentation directory once it is pushed.
+	removeBuilt func(artifactPath string) string
+}
+
+// pushManifest pushes all entries of the manifest, up to concurrency entries at a time.
+// It reports the outcome of every entry and fails if any entry failed.
+func pushManifest(ctx context.Context, manifestPath string, concurrency int, opts manifestOptions) {
+	if concurrency < 1 {
+		fail(fmt.Errorf("concurrency must be at least 1, got %d", concurrency))
+	}
+	if opts.build.Output != "" {
+		fail(errors.New("--output-file cannot be used with --manifest, use --output-dir instead"))
+	}
+
+	manifest, err := docatl.ReadManifest(util.ResolvePath(manifestPath))
+	if err != nil {
+		fail(err)
+	}
+
+	res := manifestResult{Host: docat.Host, Entries: make([]manifestEntryResult, len(manifest.Entries))}
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, entry := range manifest.Entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			entryResult, err := pushManifestEntry(ctx, entry, opts)
+			if err != nil {
+				entryResult.Error = err.Error()
+				reportError(fmt.Errorf("unable to push %s: %w", entry.Docs, err))
+			}
+			res.Entries[i] = entryResult
+		}()
+	}
+	wg.Wait()
+
+	for _, entry := range res.Entries {
+		if entry.Error != "" {
+			res.Failed++
+		}
+	}
+
+	if outputFormat == outputText {
+		printManifestSummary(res)
+	} else {
+		printResult(res)
+	}
+
+	if res.Failed > 0 {
+		fail(fmt.Errorf("%d of %d manifest entries failed", res.Failed, len(res.Entries)))
+	}
+}
+
+// pushManifestEntry builds the documentation of the entry if needed, pushes it and applies tags, icon and hidden state.
+// The tags stored in a prebuilt artifact are applied in addition to the tags of the entry.
+func pushManifestEntry(ctx context.Context, entry docatl.ManifestEntry, opts manifestOptions) (res manifestEntryResult, err error) {
+	res = manifestEntryResult{
+		Docs:    entry.Docs,
+		Project: entry.Project,
+		Version: entry.Version,
+		Tags:    entry.Tags,
+		Icon:    entry.Icon,
+		Hidden:  entry.Hidden,
+	}
+
+	docsPath := entry.Docs
+	built := util.IsDirectory(docsPath)
+	if !built {
+		if err = opts.verify(docsPath, false); err != nil {
+			return res, err
+		}
+		meta, err := docatl.ExtractMetadata(docsPath)
+		if err != nil {
+			return res, err
+		}
+		if res.Project == "" {
+			res.Project = meta.Project
+		}
+		if res.Version == "" {
+			res.Version = meta.Version
+		}
+		res.Tags = mergeTags(meta.Tags, res.Tags)
+	}
+	if res.Project == "" || res.Version == "" {
+		return res, errors.New("project and version must be given in the manifest or the artifact metadata")
+	}
+
+	if built {
+		if err = opts.lint(docsPath); err != nil {
+			return res, err
+		}
+		docsPath, err = docatl.Build(docsPath, newBuildMetadata(docsPath, res.Project, res.Version, res.Tags, opts.labels), opts.build)
+		if err != nil {
+			return res, err
+		}
+		// the artifact is removed once the entry is pushed, also if pushing failed
+		defer func() { res.Artifact = opts.removeBuilt(docsPath) }()
+		if err = opts.verify(docsPath, true); err != nil {
+			return res, err
+		}
+	}
+
+	client := docat
+	client.ApiKey = apiKeyFor(client.Host, res.Project, docat.ApiKey)
+	client.Progress = nil
+
+	if err = upload(ctx, &client, res.Project, res.Version, docsPath, res.Tags, opts.force); err != nil {
+		return res, err
+	}
+
+	if entry.Icon != "" {
+		if err = client.PushIconContext(ctx, res.Project, entry.Icon); err != nil {
+			return res, err
+		}
+		logf("Successfully pushed icon %s for project %s", entry.Icon, res.Project)
+	}
+
+	if entry.Hidden {
+		if err = client.HideOrShowVersionContext(ctx, res.Project, res.Version, true); err != nil {
+			return res, err
+		}
+		logf("Successfully hid version %s of project %s", res.Version, res.Project)
+	}
+
+	return res, nil
+}
+
+func printManifestSummary(res manifestResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "DOCS\tPROJECT\tVERSION\tRESULT")
+	for _, entry := range res.Entries {
+		outcome := "ok"
+		if entry.Error != "" {
+			outcome = "failed: " + entry.Error
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.Docs, entry.Project, entry.Version, outcome)
+	}
+	_ = w.Flush()
+}
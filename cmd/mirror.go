@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	docatl "github.com/docat-org/docatl/pkg"
+	"github.com/spf13/cobra"
+)
+
+type mirrorResult struct {
+	From     string            `json:"from" yaml:"from"`
+	To       string            `json:"to" yaml:"to"`
+	Projects []mirroredProject `json:"projects" yaml:"projects"`
+}
+
+type mirroredProject struct {
+	Name        string   `json:"name" yaml:"name"`
+	Transferred []string `json:"transferred" yaml:"transferred"`
+	Skipped     []string `json:"skipped" yaml:"skipped"`
+	Icon        bool     `json:"icon" yaml:"icon"`
+}
+
+var mirrorCmd = &cobra.Command{
+	Use:   "mirror --from URL --to URL [PROJECT...]",
+	Short: "Copy documentation from one docat server to another",
+	Long: `Copy documentation from one docat server to another.
+
+All versions missing on the destination server are downloaded from the source server
+and uploaded to the destination server, including their tags, hidden state and the project icon.
+Versions which already exist on the destination server are skipped.
+
+Mirror all projects:
+
+	docatl mirror --from https://docat-staging.company.io --to https://docat.company.io
+
+Mirror a single project:
+
+	docatl mirror --from https://docat-staging.company.io --to https://docat.company.io myproject
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		from, err := cmd.Flags().GetString("from")
+		cobra.CheckErr(err)
+		to, err := cmd.Flags().GetString("to")
+		cobra.CheckErr(err)
+		fromApiKey, err := cmd.Flags().GetString("from-api-key")
+		cobra.CheckErr(err)
+		toApiKey, err := cmd.Flags().GetString("to-api-key")
+		cobra.CheckErr(err)
+
+		if from == "" || to == "" {
+			fail(errors.New("both --from and --to must be given"))
+		}
+
+		source := docat
+		source.Host, source.ApiKey = from, fromApiKey
+		destination := docat
+		destination.Host, destination.ApiKey = to, toApiKey
+
+		sourceProjects, err := source.ListProjectsContext(cmd.Context(), true)
+		if err != nil {
+			fail(err)
+		}
+		if len(args) > 0 {
+			sourceProjects = slices.DeleteFunc(sourceProjects, func(project docatl.Project) bool {
+				return !slices.Contains(args, project.Name)
+			})
+			if len(sourceProjects) != len(args) {
+				fail(fmt.Errorf("not all of the projects %v exist on %s: %w", args, from, docatl.ErrNotFound))
+			}
+		}
+
+		destinationProjects, err := destination.ListProjectsContext(cmd.Context(), true)
+		if err != nil {
+			fail(err)
+		}
+
+		tmpDir, err := os.MkdirTemp("", "docatl-*")
+		if err != nil {
+			fail(fmt.Errorf("unable to create temp directory for mirroring: %w", err))
+		}
+		defer func() { _ = os.RemoveAll(tmpDir) }()
+
+		res := mirrorResult{From: from, To: to, Projects: []mirroredProject{}}
+		for _, project := range sourceProjects {
+			var existing docatl.Project
+			if i := slices.IndexFunc(destinationProjects, func(p docatl.Project) bool { return p.Name == project.Name }); i >= 0 {
+				existing = destinationProjects[i]
+			}
+
+			if toApiKey == "" {
+				destination.ApiKey = apiKeyFor(to, project.Name, docat.ApiKey)
+			}
+			mirrored, err := mirrorProject(cmd.Context(), &source, &destination, project, existing, tmpDir)
+			if err != nil {
+				fail(err)
+			}
+			res.Projects = append(res.Projects, mirrored)
+		}
+
+		transferred, skipped := 0, 0
+		for _, project := range res.Projects {
+			transferred += len(project.Transferred)
+			skipped += len(project.Skipped)
+		}
+		logf("Successfully mirrored %d projects from %s to %s: transferred %d versions, skipped %d existing versions", len(res.Projects), from, to, transferred, skipped)
+
+		printResult(res)
+	},
+}
+
+func mirrorProject(ctx context.Context, source *docatl.Docat, destination *docatl.Docat, project docatl.Project, existing docatl.Project, tmpDir string) (mirroredProject, error) {
+	mirrored := mirroredProject{Name: project.Name, Transferred: []string{}, Skipped: []string{}}
+
+	versions := slices.Clone(project.Versions)
+	slices.SortFunc(versions, func(a, b docatl.ProjectVersion) int {
+		return docatl.CompareVersions(a.Name, b.Name)
+	})
+
+	for _, version := range versions {
+		if slices.ContainsFunc(existing.Versions, func(v docatl.ProjectVersion) bool { return v.Name == version.Name }) {
+			mirrored.Skipped = append(mirrored.Skipped, version.Name)
+			continue
+		}
+
+		artifactPath := filepath.Join(tmpDir, fmt.Sprintf("docs_%s_%s.zip", project.Name, version.Name))
+		if err := source.DownloadContext(ctx, project.Name, version.Name, artifactPath); err != nil {
+			return mirrored, err
+		}
+		if err := destination.PostContext(ctx, project.Name, version.Name, artifactPath); err != nil {
+			return mirrored, err
+		}
+		_ = os.Remove(artifactPath)
+
+		for _, tag := range version.Tags {
+			if err := destination.TagContext(ctx, project.Name, version.Name, tag); err != nil {
+				return mirrored, err
+			}
+		}
+		if version.Hidden {
+			if err := destination.HideOrShowVersionContext(ctx, project.Name, version.Name, true); err != nil {
+				return mirrored, err
+			}
+		}
+
+		logf("Successfully mirrored version %s of project %s", version.Name, project.Name)
+		mirrored.Transferred = append(mirrored.Transferred, version.Name)
+	}
+
+	if project.Logo && !existing.Logo {
+		iconPath, err := source.DownloadIconContext(ctx, project.Name, tmpDir)
+		if err != nil {
+			return mirrored, err
+		}
+		if err = destination.PushIconContext(ctx, project.Name, iconPath); err != nil {
+			return mirrored, err
+		}
+		_ = os.Remove(iconPath)
+
+		logf("Successfully mirrored icon of project %s", project.Name)
+		mirrored.Icon = true
+	}
+
+	return mirrored, nil
+}
+
+func init() {
+	rootCmd.AddCommand(mirrorCmd)
+
+	mirrorCmd.Flags().String("from", "", "url of the docat server to copy from")
+	mirrorCmd.Flags().String("to", "", "url of the docat server to copy to")
+	mirrorCmd.Flags().String("from-api-key", "", "docat Api Key for the source server")
+	mirrorCmd.Flags().String("to-api-key", "", "docat Api Key for the destination server (defaults to --api-key)")
+}
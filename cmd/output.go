@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net"
+	"os"
+
+	docatl "github.com/docat-org/docatl/pkg"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+var outputFormat string
+
+// result is the structured outcome of a command, printed when a non-text output format is selected.
+type result struct {
+	Host     string   `json:"host,omitempty" yaml:"host,omitempty"`
+	Project  string   `json:"project,omitempty" yaml:"project,omitempty"`
+	Version  string   `json:"version,omitempty" yaml:"version,omitempty"`
+	Tags     []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Artifact string   `json:"artifact,omitempty" yaml:"artifact,omitempty"`
+	Token    string   `json:"token,omitempty" yaml:"token,omitempty"`
+	Icon     string   `json:"icon,omitempty" yaml:"icon,omitempty"`
+	NewName  string   `json:"new-name,omitempty" yaml:"new-name,omitempty"`
+	Hidden   *bool    `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+}
+
+type errorResult struct {
+	Error      string `json:"error" yaml:"error"`
+	ExitCode   int    `json:"exit-code" yaml:"exit-code"`
+	StatusCode int    `json:"status-code,omitempty" yaml:"status-code,omitempty"`
+	Endpoint   string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+}
+
+// exit codes of failed commands
+const (
+	exitFailure      = 1
+	exitNotFound     = 3
+	exitUnauthorized = 4
+	exitConflict     = 5
+	exitConnection   = 6
+	exitIntegrity    = 7
+)
+
+func exitCode(err error) int {
+	// syscall.Errno implements net.Error as well, so file system errors are told apart by *fs.PathError
+	var netErr net.Error
+	var pathErr *fs.PathError
+	switch {
+	case errors.Is(err, docatl.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, docatl.ErrUnauthorized):
+		return exitUnauthorized
+	case errors.Is(err, docatl.ErrConflict):
+		return exitConflict
+	case errors.Is(err, docatl.ErrIntegrity):
+		return exitIntegrity
+	case errors.As(err, &netErr) && !errors.As(err, &pathErr):
+		return exitConnection
+	default:
+		return exitFailure
+	}
+}
+
+func ensureOutputFormat() {
+	switch outputFormat {
+	case outputText, outputJSON, outputYAML:
+	default:
+		log.Fatalf("unknown output format '%s', must be one of: %s, %s, %s", outputFormat, outputText, outputJSON, outputYAML)
+	}
+}
+
+// logf logs a human readable message, but only in text output mode.
+func logf(format string, v ...any) {
+	if outputFormat == outputText {
+		log.Printf(format, v...)
+	}
+}
+
+// printResult writes the structured result to stdout, unless text output mode is used.
+func printResult(v any) {
+	if outputFormat == outputText {
+		return
+	}
+	cobra.CheckErr(encode(os.Stdout, v))
+}
+
+// fail reports the error in the selected output format and exits with the exit code matching the error.
+func fail(err error) {
+	reportError(err)
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+	os.Exit(exitCode(err))
+}
+
+// cleanups are run by fail before exiting, as os.Exit skips deferred functions.
+var cleanups []func()
+
+// onFail registers a cleanup, like removing temporary files, which fail runs before exiting.
+func onFail(cleanup func()) {
+	cleanups = append(cleanups, cleanup)
+}
+
+// reportError writes the error to stderr in the selected output format.
+func reportError(err error) {
+	if outputFormat != outputText {
+		res := errorResult{Error: err.Error(), ExitCode: exitCode(err)}
+		var apiErr *docatl.APIError
+		if errors.As(err, &apiErr) {
+			res.StatusCode = apiErr.StatusCode
+			res.Endpoint = apiErr.Endpoint
+		}
+		if encodeErr := encode(os.Stderr, res); encodeErr == nil {
+			return
+		}
+	}
+	log.Print(err)
+}
+
+func encode(w io.Writer, v any) error {
+	switch outputFormat {
+	case outputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case outputYAML:
+		encoder := yaml.NewEncoder(w)
+		defer func() { _ = encoder.Close() }()
+		return encoder.Encode(v)
+	default:
+		return fmt.Errorf("cannot encode result for output format '%s'", outputFormat)
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	docatl "github.com/docat-org/docatl/pkg"
+)
+
+const progressBarWidth = 40
+
+// uploadProgress returns a progress callback rendering a progress bar if stdout is a terminal,
+// and logging the percentage uploaded every 10% otherwise. Progress is only shown for text output.
+func uploadProgress() docatl.ProgressFunc {
+	if outputFormat != outputText {
+		return nil
+	}
+
+	terminal := isTerminal(os.Stdout)
+	last := -1
+	return func(sent int64, total int64) {
+		percent := 100
+		if total > 0 {
+			percent = int(sent * 100 / total)
+		}
+		if sent == 0 {
+			// the upload (re)started
+			last = -1
+		}
+
+		if terminal {
+			if percent == last {
+				return
+			}
+			last = percent
+
+			filled := percent * progressBarWidth / 100
+			fmt.Printf("\r[%s%s] %3d%% of %s", strings.Repeat("=", filled), strings.Repeat(" ", progressBarWidth-filled), percent, formatBytes(total))
+			if sent == total {
+				fmt.Println()
+			}
+			return
+		}
+
+		if step := percent / 10 * 10; step > 0 && step > last {
+			last = step
+			logf("Uploaded %d%% of %s", step, formatBytes(total))
+		}
+	}
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"text/tabwriter"
+	"time"
+
+	docatl "github.com/docat-org/docatl/pkg"
+	"github.com/spf13/cobra"
+)
+
+type pruneResult struct {
+	Host    string   `json:"host" yaml:"host"`
+	Project string   `json:"project" yaml:"project"`
+	DryRun  bool     `json:"dry-run" yaml:"dry-run"`
+	Deleted []string `json:"deleted" yaml:"deleted"`
+	Kept    []string `json:"kept" yaml:"kept"`
+}
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune PROJECT",
+	Short: "Delete old documentation versions from a docat server",
+	Long: `Delete old documentation versions from a docat server.
+
+Versions are deleted unless they are kept by one of the retention rules:
+one of the newest versions (by semantic versioning), tagged or matching a pattern.
+With --older-than only versions uploaded before that duration are deleted.
+
+Show which versions would be deleted:
+
+	docatl prune myproject --keep 5 --dry-run
+
+Delete all untagged versions older than 30 days, except major releases:
+
+	docatl prune myproject --older-than 720h --keep-regex '^v?[0-9]+\.0\.0$'
+`,
+	Args: cobra.ExactArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		ensureHost()
+		resolveApiKey(args[0])
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		project := args[0]
+
+		keep, err := cmd.Flags().GetInt("keep")
+		cobra.CheckErr(err)
+		keepTagged, err := cmd.Flags().GetBool("keep-tagged")
+		cobra.CheckErr(err)
+		keepRegex, err := cmd.Flags().GetString("keep-regex")
+		cobra.CheckErr(err)
+		olderThan, err := cmd.Flags().GetDuration("older-than")
+		cobra.CheckErr(err)
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		cobra.CheckErr(err)
+
+		if keep <= 0 && olderThan <= 0 {
+			fail(errors.New("refusing to prune without a retention policy, use --keep and/or --older-than"))
+		}
+
+		policy := docatl.PrunePolicy{
+			Keep:       keep,
+			KeepTagged: keepTagged,
+			OlderThan:  olderThan,
+		}
+		if keepRegex != "" {
+			policy.KeepPattern, err = regexp.Compile(keepRegex)
+			if err != nil {
+				fail(fmt.Errorf("invalid --keep-regex: %w", err))
+			}
+		}
+
+		details, err := docat.GetProjectContext(cmd.Context(), project, true)
+		if err != nil {
+			fail(err)
+		}
+
+		plan := policy.Plan(details.Versions, time.Now())
+		res := pruneResult{
+			Host:    docat.Host,
+			Project: project,
+			DryRun:  dryRun,
+			Deleted: []string{},
+			Kept:    []string{},
+		}
+
+		if dryRun && outputFormat == outputText {
+			printPrunePlan(plan)
+		}
+
+		for _, decision := range plan {
+			version := decision.Version.Name
+			if !decision.Delete {
+				res.Kept = append(res.Kept, version)
+				continue
+			}
+
+			if !dryRun {
+				if err := docat.DeleteContext(cmd.Context(), project, version); err != nil {
+					fail(err)
+				}
+				logf("Successfully deleted version %s of project %s", version, project)
+			}
+			res.Deleted = append(res.Deleted, version)
+		}
+
+		if !dryRun {
+			logf("Pruned %d and kept %d versions of project %s", len(res.Deleted), len(res.Kept), project)
+		}
+
+		printResult(res)
+	},
+}
+
+func printPrunePlan(plan []docatl.PruneDecision) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "VERSION\tACTION\tREASON")
+	for _, decision := range plan {
+		action := "keep"
+		if decision.Delete {
+			action = "delete"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", decision.Version.Name, action, decision.Reason)
+	}
+	_ = w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+
+	pruneCmd.Flags().IntP("keep", "k", 0, "number of newest versions to keep")
+	pruneCmd.Flags().Bool("keep-tagged", true, "keep versions which have a tag")
+	pruneCmd.Flags().String("keep-regex", "", "keep versions matching this regular expression")
+	pruneCmd.Flags().Duration("older-than", 0, "only delete versions uploaded longer ago than this (e.g. 720h)")
+	pruneCmd.Flags().Bool("dry-run", false, "only print which versions would be deleted")
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	util "github.com/docat-org/docatl/internal"
+	docatl "github.com/docat-org/docatl/pkg"
+	"github.com/spf13/cobra"
+)
+
+var pullCmd = &cobra.Command{
+	Use:   "pull PROJECT VERSION [DEST]",
+	Short: "Download documentation from a docat server",
+	Long: `Download documentation from a docat server.
+
+The VERSION can also be a tag, like 'latest'.
+If DEST ends with '.zip' (or is omitted), a documentation artifact is written,
+which can be pushed again with 'docatl push'. Otherwise the documentation
+is extracted into the DEST directory.
+
+Download documentation as artifact:
+
+	docatl pull myproject latest
+
+Download documentation into a directory:
+
+	docatl pull myproject 1.0.0 ./docs/
+`,
+	Args: cobra.RangeArgs(2, 3),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		ensureHost()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		project, nameOrTag := args[0], args[1]
+
+		details, err := docat.GetProjectContext(cmd.Context(), project, true)
+		if err != nil {
+			fail(err)
+		}
+		resolved, ok := details.ResolveVersion(nameOrTag)
+		if !ok {
+			fail(fmt.Errorf("project %s has no version or tag %s: %w", project, nameOrTag, docatl.ErrNotFound))
+		}
+		version := resolved.Name
+
+		tmpDir, err := os.MkdirTemp("", "docatl-*")
+		if err != nil {
+			fail(fmt.Errorf("unable to create temp directory for download: %w", err))
+		}
+		defer func() { _ = os.RemoveAll(tmpDir) }()
+
+		downloadPath := filepath.Join(tmpDir, "download.zip")
+		if err = docat.DownloadContext(cmd.Context(), project, version, downloadPath); err != nil {
+			fail(err)
+		}
+
+		docsPath := filepath.Join(tmpDir, "docs")
+		if len(args) == 3 && !strings.HasSuffix(args[2], ".zip") {
+			docsPath = util.ResolvePath(args[2])
+		}
+		if err = docatl.Unpack(downloadPath, docsPath); err != nil {
+			fail(err)
+		}
+
+		outputPath := docsPath
+		if len(args) < 3 || strings.HasSuffix(args[2], ".zip") {
+			var opts docatl.BuildOptions
+			if len(args) == 3 {
+				opts.Output = args[2]
+			}
+
+			outputPath, err = docatl.Build(docsPath, docatl.BuildMetadata{
+				Host:    docat.Host,
+				Project: project,
+				Version: version,
+			}, opts)
+			if err != nil {
+				fail(fmt.Errorf("unable to build documentation artifact: %w", err))
+			}
+		}
+
+		logf("Successfully pulled version %s of project %s to %s", version, project, outputPath)
+
+		printResult(result{
+			Host:     docat.Host,
+			Project:  project,
+			Version:  version,
+			Artifact: outputPath,
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pullCmd)
+}
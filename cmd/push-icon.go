@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"log"
-
 	"github.com/spf13/cobra"
 )
 
@@ -16,17 +14,24 @@ Push an icon for a project:
 	Args: cobra.ExactArgs(2),
 	PreRun: func(cmd *cobra.Command, args []string) {
 		ensureHost()
+		resolveApiKey(args[0])
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		project, iconPath := args[0], args[1]
 
-		err := docat.PushIcon(project, iconPath)
+		err := docat.PushIconContext(cmd.Context(), project, iconPath)
 
 		if err != nil {
-			log.Fatal(err)
+			fail(err)
 		}
 
-		log.Printf("Successfully pushed icon %s for project %s", iconPath, project)
+		logf("Successfully pushed icon %s for project %s", iconPath, project)
+
+		printResult(result{
+			Host:    docat.Host,
+			Project: project,
+			Icon:    iconPath,
+		})
 	},
 }
 
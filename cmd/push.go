@@ -1,7 +1,13 @@
 package cmd
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	util "github.com/docat-org/docatl/internal"
 	docatl "github.com/docat-org/docatl/pkg"
@@ -22,27 +28,59 @@ Build & Upload documentation:
 
 	docatl push ./docs/ myproject 1.0.0 -t latest
 
+Build & Upload documentation with the project and version taken from git:
+
+	docatl push ./docs/ --project-from git --version-from git
+
+Upload a documentation artifact, applying the tags stored in its metadata and the given ones:
+
+	docatl push ./docs.zip -t stable
+
+Upload a documentation artifact only if it is signed with the private key matching docatl.pub:
+
+	docatl push ./docs.zip --public-key docatl.pub --require-signature
+
+Build & Upload documentation without linting it first:
+
+	docatl push ./docs/ myproject 1.0.0 --no-lint
+
+Replace an existing version, keeping its tags:
+
+	docatl push ./docs/ myproject dev --force
+
+Rebuild & Upload documentation whenever it changes, replacing the version if it already exists:
+
+	docatl push ./docs/ myproject dev --watch
+
+Build & Upload all documentation listed in a manifest:
+
+	docatl push --manifest docatl-manifest.yaml
+
 Upload documentation to specific docat server:
 
 	docatl push --host https://localhost:8000 ./docs.zip myproject 1.0.0 -t latest
 `,
-	Args: cobra.RangeArgs(1, 3),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if manifest, _ := cmd.Flags().GetString("manifest"); manifest != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.RangeArgs(1, 3)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
-		docsPath := util.ResolvePath(args[0])
 		project := viper.GetString("project")
 		version := viper.GetString("version")
 
 		unpackArgs := func() (string, string) {
 			if project == "" {
 				if len(args) < 2 {
-					log.Fatalf("when PROJECT is not given, the DOCATL_PROJECT variable must contain it")
+					fail(errors.New("when PROJECT is not given, the DOCATL_PROJECT variable or --project-from must provide it"))
 				}
 				project = args[1]
 			}
 
 			if version == "" {
 				if len(args) < 3 {
-					log.Fatalf("when VERSION is not given, the DOCATL_VERSION variable must contain it")
+					fail(errors.New("when VERSION is not given, the DOCATL_VERSION variable or --version-from must provide it"))
 				} else {
 					version = args[2]
 				}
@@ -51,23 +89,140 @@ Upload documentation to specific docat server:
 			return project, version
 		}
 
-		if util.IsDirectory(docsPath) {
+		watch, err := cmd.Flags().GetBool("watch")
+		cobra.CheckErr(err)
+		tags, err := cmd.Flags().GetStringSlice("tag")
+		cobra.CheckErr(err)
+		keepArtifact, err := cmd.Flags().GetBool("keep-artifact")
+		cobra.CheckErr(err)
+		force, err := cmd.Flags().GetBool("force")
+		cobra.CheckErr(err)
+		manifest, err := cmd.Flags().GetString("manifest")
+		cobra.CheckErr(err)
+		concurrency, err := cmd.Flags().GetInt("concurrency")
+		cobra.CheckErr(err)
+		opts := buildOptions(cmd)
+		labels := buildLabels(cmd)
+		requireSignature, err := cmd.Flags().GetBool("require-signature")
+		cobra.CheckErr(err)
+		publicKey := publicKeyFlag(cmd)
+		if requireSignature && publicKey == nil {
+			fail(errors.New("--require-signature requires the --public-key to verify the signature with"))
+		}
+		noLint, err := cmd.Flags().GetBool("no-lint")
+		cobra.CheckErr(err)
+		strict, err := cmd.Flags().GetBool("strict")
+		cobra.CheckErr(err)
+		rules := lintRules(cmd)
+		rules.Exclude, rules.Include = opts.Exclude, opts.Include
 
-			project, version = unpackArgs()
+		// artifacts are only built into a temp directory and removed after pushing them,
+		// unless they are kept with --keep-artifact or written to --output-file or --output-dir
+		tmpDir := ""
+		if !keepArtifact && opts.Output == "" && opts.OutputDir == "" {
+			tmpDir, err = os.MkdirTemp("", "docatl-*")
+			if err != nil {
+				fail(fmt.Errorf("unable to create temp directory for the artifact: %w", err))
+			}
+			removeTmpDir := func() { _ = os.RemoveAll(tmpDir) }
+			defer removeTmpDir()
+			onFail(removeTmpDir)
+			opts.OutputDir = tmpDir
+		}
+
+		// removeBuilt removes an artifact built by push into its temp directory.
+		removeBuilt := func(artifactPath string) string {
+			if tmpDir == "" {
+				return artifactPath
+			}
+			if err := os.Remove(artifactPath); err != nil {
+				logf("unable to remove artifact '%s': %s", artifactPath, err)
+			}
+			if err := os.Remove(artifactPath + docatl.ChecksumFileSuffix); err != nil {
+				logf("unable to remove checksum file of artifact '%s': %s", artifactPath, err)
+			}
+			return ""
+		}
+
+		// lint lints a documentation directory before building it, unless disabled with --no-lint.
+		lint := func(docsPath string) error {
+			if noLint {
+				return nil
+			}
+			return lintDocs(docsPath, rules, strict)
+		}
+
+		// verify verifies an artifact before pushing it, artifacts built by push only if a signature is to be checked.
+		verify := func(artifactPath string, built bool) error {
+			if built && publicKey == nil {
+				return nil
+			}
+			return verifyBeforePush(artifactPath, publicKey, requireSignature)
+		}
 
-			docsPathBuilt, err := docatl.Build(docsPath, docatl.BuildMetadata{
-				Host:    docat.Host,
-				Project: project,
-				Version: version,
+		if manifest != "" {
+			if watch {
+				fail(errors.New("--watch cannot be used with --manifest"))
+			}
+			ensureHost()
+			pushManifest(cmd.Context(), manifest, concurrency, manifestOptions{
+				build:       opts,
+				labels:      labels,
+				force:       force,
+				lint:        lint,
+				verify:      verify,
+				removeBuilt: removeBuilt,
 			})
+			return
+		}
+
+		docsPath := util.ResolvePath(args[0])
+		sourcePath := docsPath
+		built := false
+		var artifacts builtArtifacts
+		// applyDerived overrides project and version with the ones derived with --project-from and --version-from.
+		applyDerived := func(dir string) {
+			if derived := derivedProject(cmd, dir); derived != "" {
+				project = derived
+			}
+			if derived := derivedVersion(cmd, dir); derived != "" {
+				version = derived
+			}
+		}
+
+		if util.IsDirectory(docsPath) {
+			applyDerived(docsPath)
+			project, version = unpackArgs()
+
+			if err = lint(docsPath); err != nil {
+				fail(err)
+			}
+			docsPathBuilt, err := docatl.Build(docsPath, newBuildMetadata(docsPath, project, version, tags, labels), opts)
 			if err != nil {
-				log.Fatal(err)
+				fail(err)
 			}
 			docsPath = docsPathBuilt
+			built = true
+			artifacts.add(docsPath)
+			onFail(func() {
+				if built {
+					removeBuilt(docsPathBuilt)
+				}
+			})
+			if err = verify(docsPath, true); err != nil {
+				fail(err)
+			}
 		} else {
+			if watch {
+				fail(errors.New("--watch requires DOCS to be a documentation directory"))
+			}
+			if err = verify(docsPath, false); err != nil {
+				fail(err)
+			}
+
 			meta, err := docatl.ExtractMetadata(docsPath)
 			if err != nil {
-				log.Fatal(err)
+				fail(err)
 			}
 
 			if meta.Host != "" {
@@ -76,35 +231,124 @@ Upload documentation to specific docat server:
 
 			project = meta.Project
 			version = meta.Version
+			tags = mergeTags(meta.Tags, tags)
+			applyDerived(filepath.Dir(docsPath))
 
 			project, version = unpackArgs()
 		}
 
 		ensureHost()
+		resolveApiKey(project)
+		docat.Progress = uploadProgress()
 
-		err := docat.Post(project, version, docsPath)
-		if err != nil {
-			log.Fatal(err)
+		// watch mode replaces the version on every change, so it may exist already as well
+		if err = upload(cmd.Context(), &docat, project, version, docsPath, tags, force || watch); err != nil {
+			fail(err)
+		}
+		if built {
+			docsPath = removeBuilt(docsPath)
+			built = false
 		}
 
-		log.Printf("Successfully pushed documentation version %s to project %s", version, project)
+		printResult(result{
+			Host:     docat.Host,
+			Project:  project,
+			Version:  version,
+			Tags:     tags,
+			Artifact: docsPath,
+		})
 
-		tags, err := cmd.Flags().GetStringSlice("tag")
-		cobra.CheckErr(err)
-		for _, tag := range tags {
-			err = docat.Tag(project, version, tag)
-			if err != nil {
-				log.Fatal(err)
-			}
+		if watch {
+			watchDocs(cmd, sourcePath, &artifacts, func() error {
+				if err := lint(sourcePath); err != nil {
+					return err
+				}
+				docsPathBuilt, err := docatl.Build(sourcePath, newBuildMetadata(sourcePath, project, version, tags, labels), opts)
+				if err != nil {
+					return err
+				}
+				artifacts.add(docsPathBuilt)
 
-			log.Printf("Successfully tagged version %s of project %s as %s", version, project, tag)
+				err = verify(docsPathBuilt, true)
+				if err == nil {
+					err = upload(cmd.Context(), &docat, project, version, docsPathBuilt, tags, true)
+				}
+				docsPathBuilt = removeBuilt(docsPathBuilt)
+				if err != nil {
+					return err
+				}
+
+				printResult(result{
+					Host:     docat.Host,
+					Project:  project,
+					Version:  version,
+					Tags:     tags,
+					Artifact: docsPathBuilt,
+				})
+				return nil
+			})
 		}
 	},
 }
 
+// upload pushes the documentation artifact and applies the given tags.
+// With force, an existing version is replaced, keeping its tags.
+func upload(ctx context.Context, client *docatl.Docat, project string, version string, docsPath string, tags []string, force bool) error {
+	err := client.PostContext(ctx, project, version, docsPath)
+	if force && errors.Is(err, docatl.ErrConflict) {
+		var replaced docatl.ProjectVersion
+		replaced, err = client.ReplaceContext(ctx, project, version, docsPath)
+		if err != nil {
+			return err
+		}
+
+		logf("Successfully replaced documentation version %s of project %s", version, project)
+		if len(replaced.Tags) > 0 {
+			logf("Retained tags %s of version %s", strings.Join(replaced.Tags, ", "), version)
+		}
+	} else if err != nil {
+		return err
+	} else {
+		logf("Successfully pushed documentation version %s to project %s", version, project)
+	}
+
+	for _, tag := range tags {
+		err = client.TagContext(ctx, project, version, tag)
+		if err != nil {
+			return err
+		}
+
+		logf("Successfully tagged version %s of project %s as %s", version, project, tag)
+	}
+
+	return nil
+}
+
+// mergeTags returns the tags stored in an artifact followed by the given tags it doesn't contain yet.
+func mergeTags(stored []string, tags []string) []string {
+	merged := slices.Clone(stored)
+	for _, tag := range tags {
+		if !slices.Contains(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
+
 func init() {
 	rootCmd.AddCommand(pushCmd)
 	pushCmd.PersistentFlags().StringSliceP("tag", "t", []string{}, "Additional Tag for this version (repeatable)")
+	addDeriveFlags(pushCmd)
+	addBuildFlags(pushCmd)
+	pushCmd.Flags().Bool("keep-artifact", false, "keep the artifact built from a documentation directory after pushing it, implied by --output-file and --output-dir")
+	pushCmd.Flags().BoolP("force", "f", false, "replace the version if it already exists, keeping its tags")
+	pushCmd.Flags().String("public-key", "", "PEM encoded ed25519 public key to verify the signature of signed artifacts with")
+	pushCmd.Flags().Bool("require-signature", false, "refuse to push artifacts which aren't signed with the key matching --public-key")
+	addWatchFlags(pushCmd)
+	pushCmd.Flags().String("manifest", "", "push all documentation listed in the manifest file instead of DOCS")
+	pushCmd.Flags().Int("concurrency", 4, "number of manifest entries pushed in parallel")
+	pushCmd.Flags().Bool("no-lint", false, "push documentation directories without linting them, see 'docatl lint'")
+	addLintFlags(pushCmd)
 
 	setupEnv(pushCmd)
 }
@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"log"
-
 	"github.com/spf13/cobra"
 )
 
@@ -16,17 +14,24 @@ Rename a project:
 	Args: cobra.ExactArgs(2),
 	PreRun: func(cmd *cobra.Command, args []string) {
 		ensureHost()
+		resolveApiKey(args[0])
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		project, newName := args[0], args[1]
 
-		err := docat.Rename(project, newName)
+		err := docat.RenameContext(cmd.Context(), project, newName)
 
 		if err != nil {
-			log.Fatal(err)
+			fail(err)
 		}
 
-		log.Printf("Successfully renamed project %s to %s", project, newName)
+		logf("Successfully renamed project %s to %s", project, newName)
+
+		printResult(result{
+			Host:    docat.Host,
+			Project: project,
+			NewName: newName,
+		})
 	},
 }
 
@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"time"
 
 	docatl "github.com/docat-org/docatl/pkg"
 	"github.com/spf13/cobra"
@@ -20,7 +24,19 @@ const (
 	configFileType = "yaml"
 )
 
-var cfgFile string
+var (
+	cfgFile      string
+	profile      string
+	timeout      time.Duration
+	retries      int
+	retryMaxWait time.Duration
+
+	// explicitApiKey is true if the api key is given with --api-key or DOCATL_API_KEY,
+	// which takes precedence over the api keys of projects in the config.
+	explicitApiKey bool
+	// profileErr is set if the selected profile does not exist, which only the config commands tolerate.
+	profileErr error
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "docatl",
@@ -34,13 +50,37 @@ Upload documentation:
 Upload documentation to specific docat server:
 
 	docatl push --host https://localhost:8000 ./docs.zip myproject 1.0.0 -t latest
+
+Exit codes:
+
+	1  general failure
+	3  project, version or tag not found
+	4  missing or invalid api key
+	5  project, version or tag already exists
+	6  docat server not reachable
+	7  artifact doesn't match its checksums
 `,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// the config commands must keep working to fix the selected profile
+		if profileErr != nil {
+			if !isConfigCommand(cmd) {
+				fail(profileErr)
+			}
+			fmt.Fprintln(os.Stderr, "Ignoring profile:", profileErr)
+		}
+
+		// apply env variables and the config, including the selected profile, to the flags of the command
+		bindFlags(cmd, viper.GetViper())
+	},
 }
 
 var docat docatl.Docat
 
 func Execute() {
-	cobra.CheckErr(rootCmd.Execute())
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cobra.CheckErr(rootCmd.ExecuteContext(ctx))
 }
 
 func init() {
@@ -51,13 +91,19 @@ func init() {
 	defaultConfigPath := filepath.Join(cwd, fmt.Sprintf("%s.%s", configFileName, configFileType))
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", defaultConfigPath, "config file")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "config profile to use (overrides the profile selected in the config file)")
+	cobra.CheckErr(viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile")))
 	rootCmd.PersistentFlags().StringVar(&docat.Host, "host", "", "docat hostname (e.g. https://docat.company.com:8000)")
 	rootCmd.PersistentFlags().StringVar(&docat.ApiKey, "api-key", "", "docat Api Key")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "timeout of each request to docat (e.g. 30s, 0 means no timeout)")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", docatl.DefaultRetryPolicy.Retries, "number of retries of requests failing with transient errors")
+	rootCmd.PersistentFlags().DurationVar(&retryMaxWait, "retry-max-wait", docatl.DefaultRetryPolicy.MaxWait, "maximum wait between retries")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputText, "output format (text, json or yaml)")
 }
 
 func ensureHost() {
 	if docat.Host == "" {
-		log.Fatal("host setting is missing. Either use `--host <host>` or `DOCATL_HOST=<host>` or a config file with the `host:` field.")
+		fail(errors.New("host setting is missing. Either use `--host <host>` or `DOCATL_HOST=<host>` or a config file with the `host:` field."))
 	}
 }
 
@@ -83,7 +129,52 @@ func initConfig() {
 		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
 
+	viper.SetEnvPrefix(envPrefix)
+	viper.AutomaticEnv()
+	applyProfile()
+
+	explicitApiKey = rootCmd.PersistentFlags().Changed("api-key") || os.Getenv(envPrefix+"_API_KEY") != ""
+
 	setupEnv(rootCmd)
+	ensureOutputFormat()
+
+	if timeout > 0 {
+		docat.Client = &http.Client{Timeout: timeout}
+	}
+	docat.Retry = docatl.DefaultRetryPolicy
+	docat.Retry.Retries = retries
+	docat.Retry.MaxWait = retryMaxWait
+}
+
+// applyProfile merges the settings of the selected profile into the config.
+// The profile is selected with --profile, DOCATL_PROFILE or the `profile` key of the config file.
+func applyProfile() {
+	name := viper.GetString("profile")
+	if name == "" {
+		return
+	}
+
+	config, err := docatl.ReadConfig(viper.ConfigFileUsed())
+	if err != nil {
+		fail(err)
+	}
+	selected, ok := config.Profiles[name]
+	if !ok {
+		profileErr = fmt.Errorf("profile '%s' does not exist in config file '%s'", name, viper.ConfigFileUsed())
+		return
+	}
+
+	cobra.CheckErr(viper.MergeConfigMap(selected.Settings(config)))
+	fmt.Fprintln(os.Stderr, "Using profile:", name)
+}
+
+func isConfigCommand(cmd *cobra.Command) bool {
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if cmd == configCmd {
+			return true
+		}
+	}
+	return false
 }
 
 func setupEnv(cmd *cobra.Command) {
@@ -102,6 +193,20 @@ func bindFlags(cmd *cobra.Command, v *viper.Viper) {
 		}
 
 		if !f.Changed && v.IsSet(f.Name) {
+			if sliceValue, ok := f.Value.(pflag.SliceValue); ok {
+				cobra.CheckErr(sliceValue.Replace(v.GetStringSlice(f.Name)))
+				f.Changed = true
+				return
+			}
+			if f.Value.Type() == "stringToString" {
+				var pairs []string
+				for key, value := range v.GetStringMapString(f.Name) {
+					pairs = append(pairs, key+"="+value)
+				}
+				cobra.CheckErr(cmd.Flags().Set(f.Name, strings.Join(pairs, ",")))
+				return
+			}
+
 			val := v.Get(f.Name)
 			err := cmd.Flags().Set(f.Name, fmt.Sprintf("%v", val))
 			cobra.CheckErr(err)
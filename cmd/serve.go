@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	util "github.com/docat-org/docatl/internal"
+	docatl "github.com/docat-org/docatl/pkg"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve PATH",
+	Short: "Preview documentation locally like a docat server would serve it",
+	Long: `Preview documentation locally like a docat server would serve it.
+
+PATH can either be a documentation directory or an artifact built with 'docatl build'.
+The project and version are read from the artifact metadata, if present.
+
+Preview a documentation directory:
+
+	docatl serve ./docs/ --project myproject --version 1.0.0
+
+Preview a documentation artifact:
+
+	docatl serve ./docs_myproject_1.0.0.zip --listen localhost:9000
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		docsPath := util.ResolvePath(args[0])
+		project, err := cmd.Flags().GetString("project")
+		cobra.CheckErr(err)
+		version, err := cmd.Flags().GetString("version")
+		cobra.CheckErr(err)
+		listen, err := cmd.Flags().GetString("listen")
+		cobra.CheckErr(err)
+
+		if !util.IsDirectory(docsPath) {
+			meta, err := docatl.ExtractMetadata(docsPath)
+			if err != nil {
+				fail(err)
+			}
+			if project == "" {
+				project = meta.Project
+			}
+			if version == "" {
+				version = meta.Version
+			}
+
+			tmpDir, err := os.MkdirTemp("", "docatl-*")
+			if err != nil {
+				fail(fmt.Errorf("unable to create temp directory to unpack artifact: %w", err))
+			}
+			defer func() { _ = os.RemoveAll(tmpDir) }()
+
+			if err = docatl.Unpack(docsPath, tmpDir); err != nil {
+				fail(err)
+			}
+			docsPath = tmpDir
+		}
+
+		if project == "" {
+			project = strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+		}
+		if version == "" {
+			version = "latest"
+		}
+
+		prefix := fmt.Sprintf("/%s/%s/", url.PathEscape(project), url.PathEscape(version))
+		mux := http.NewServeMux()
+		mux.Handle(prefix, http.StripPrefix(prefix, http.FileServer(http.Dir(docsPath))))
+		mux.Handle("/{$}", http.RedirectHandler(prefix, http.StatusFound))
+
+		server := &http.Server{Addr: listen, Handler: mux}
+		go func() {
+			<-cmd.Context().Done()
+			_ = server.Shutdown(context.Background())
+		}()
+
+		log.Printf("Serving version %s of project %s at http://%s%s (press Ctrl+C to stop)", version, project, listen, prefix)
+		if err = server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fail(fmt.Errorf("unable to serve documentation: %w", err))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringP("project", "p", "", "the name of the docat project (defaults to the artifact metadata)")
+	serveCmd.Flags().StringP("version", "v", "", "the version of this documentation (defaults to the artifact metadata)")
+	serveCmd.Flags().StringP("listen", "l", "localhost:8000", "address to serve the documentation on")
+}
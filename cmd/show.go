@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"log"
-
 	"github.com/spf13/cobra"
 )
 
@@ -18,17 +16,26 @@ Show a project version:
 	Args: cobra.ExactArgs(2),
 	PreRun: func(cmd *cobra.Command, args []string) {
 		ensureHost()
+		resolveApiKey(args[0])
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		project, version := args[0], args[1]
 
-		err := docat.HideOrShowVersion(project, version, false)
+		err := docat.HideOrShowVersionContext(cmd.Context(), project, version, false)
 
 		if err != nil {
-			log.Fatal(err)
+			fail(err)
 		}
 
-		log.Printf("Successfully undid hiding version %s of project %s", version, project)
+		logf("Successfully undid hiding version %s of project %s", version, project)
+
+		hidden := false
+		printResult(result{
+			Host:    docat.Host,
+			Project: project,
+			Version: version,
+			Hidden:  &hidden,
+		})
 	},
 }
 
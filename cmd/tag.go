@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"log"
-
 	"github.com/spf13/cobra"
 )
 
@@ -18,18 +16,26 @@ Tag documentation:
 	Args: cobra.MinimumNArgs(3),
 	PreRun: func(cmd *cobra.Command, args []string) {
 		ensureHost()
+		resolveApiKey(args[0])
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		project, version, tags := args[0], args[1], args[2:]
 
 		for _, tag := range tags {
-			err := docat.Tag(project, version, tag)
+			err := docat.TagContext(cmd.Context(), project, version, tag)
 			if err != nil {
-				log.Fatal(err)
+				fail(err)
 			}
 
-			log.Printf("Successfully tagged version %s of project %s as %s", version, project, tag)
+			logf("Successfully tagged version %s of project %s as %s", version, project, tag)
 		}
+
+		printResult(result{
+			Host:    docat.Host,
+			Project: project,
+			Version: version,
+			Tags:    tags,
+		})
 	},
 }
 
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	docatl "github.com/docat-org/docatl/pkg"
+	"github.com/spf13/cobra"
+)
+
+// verifyResult is the structured outcome of the verify command.
+type verifyResult struct {
+	Artifact            string `json:"artifact" yaml:"artifact"`
+	docatl.Verification `yaml:",inline"`
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify ARTIFACT",
+	Short: "Check a documentation artifact against its checksums and signature",
+	Long: `Check a documentation artifact against its checksums and signature.
+
+The digest of the artifact is checked against the '.sha256' file next to it
+and every file against its checksum in the artifact metadata, both written by 'docatl build'.
+With --public-key, the artifact must be signed with the matching private key.
+Artifacts which don't match fail with exit code 7.
+
+	docatl verify docs_myproject_1.0.0.zip
+	docatl verify docs_myproject_1.0.0.zip --public-key docatl.pub
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		artifactPath := args[0]
+		publicKey := publicKeyFlag(cmd)
+
+		verification, err := docatl.VerifyArtifact(artifactPath, publicKey)
+		if err != nil {
+			fail(err)
+		}
+		if publicKey != nil && !verification.Signed {
+			fail(fmt.Errorf("unable to verify artifact '%s' because it is not signed: %w", artifactPath, docatl.ErrIntegrity))
+		}
+		if !verification.Verified() {
+			fail(fmt.Errorf("unable to verify artifact '%s' because it has neither a checksum file nor checksums in its metadata", artifactPath))
+		}
+
+		if verification.DigestVerified {
+			logf("Digest %s of artifact %s matches its checksum file", verification.Digest, artifactPath)
+		}
+		if verification.FilesVerified > 0 {
+			logf("All %d files of artifact %s match their checksums", verification.FilesVerified, artifactPath)
+		}
+		if verification.SignatureVerified {
+			logf("Signature of artifact %s matches the public key", artifactPath)
+		}
+
+		printResult(verifyResult{Artifact: artifactPath, Verification: verification})
+	},
+}
+
+// publicKeyFlag reads the public key given with --public-key, if any.
+func publicKeyFlag(cmd *cobra.Command) ed25519.PublicKey {
+	path, err := cmd.Flags().GetString("public-key")
+	cobra.CheckErr(err)
+	if path == "" {
+		return nil
+	}
+
+	publicKey, err := docatl.ReadPublicKey(path)
+	if err != nil {
+		fail(err)
+	}
+	return publicKey
+}
+
+// verifyBeforePush verifies the artifact if it has a checksum file or checksums in its metadata,
+// and its signature if a public key is given. With requireSignature, unsigned artifacts are refused.
+func verifyBeforePush(artifactPath string, publicKey ed25519.PublicKey, requireSignature bool) error {
+	verification, err := docatl.VerifyArtifact(artifactPath, publicKey)
+	if err != nil {
+		if errors.Is(err, docatl.ErrIntegrity) {
+			return fmt.Errorf("refusing to push: %w", err)
+		}
+		return err
+	}
+	if requireSignature && !verification.Signed {
+		return fmt.Errorf("refusing to push: artifact '%s' is not signed: %w", artifactPath, docatl.ErrIntegrity)
+	}
+	if verification.Verified() {
+		logf("Verified artifact %s", artifactPath)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().String("public-key", "", "PEM encoded ed25519 public key the artifact must be signed with")
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"slices"
+	"time"
+
+	docatl "github.com/docat-org/docatl/pkg"
+	"github.com/spf13/cobra"
+)
+
+const defaultDebounce = 500 * time.Millisecond
+
+func addWatchFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("watch", false, "rebuild whenever the documentation directory changes")
+	cmd.Flags().Duration("debounce", defaultDebounce, "time to wait for further changes before rebuilding in watch mode")
+}
+
+// builtArtifacts records the artifacts built in watch mode, so writing them
+// into the documentation directory doesn't trigger another rebuild.
+type builtArtifacts []string
+
+func (artifacts *builtArtifacts) add(artifactPath string) {
+	if !artifacts.contains(artifactPath) {
+		*artifacts = append(*artifacts, artifactPath)
+	}
+}
+
+// contains reports whether path is one of the artifacts or their checksum files.
+func (artifacts *builtArtifacts) contains(path string) bool {
+	return slices.ContainsFunc(*artifacts, func(artifactPath string) bool {
+		return docatl.IsArtifactFile(path, artifactPath)
+	})
+}
+
+// watchDocs runs cycle whenever the documentation directory changes, until interrupted.
+// Changes of the built artifacts are ignored. Errors of a cycle are reported, but do not stop watching.
+func watchDocs(cmd *cobra.Command, docsPath string, artifacts *builtArtifacts, cycle func() error) {
+	debounce, err := cmd.Flags().GetDuration("debounce")
+	cobra.CheckErr(err)
+
+	logf("Watching %s for changes (press Ctrl+C to stop)", docsPath)
+	err = docatl.Watch(cmd.Context(), docsPath, debounce, artifacts.contains, func() {
+		if err := cycle(); err != nil {
+			reportError(err)
+		}
+	})
+	if err != nil {
+		fail(err)
+	}
+}
@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// GitShortCommit returns the abbreviated hash of the HEAD commit of the git repository containing dir.
+func GitShortCommit(dir string) (string, error) {
+	return git(dir, "rev-parse", "--short", "HEAD")
+}
+
+// GitDescribe returns the nearest tag of HEAD as described by git describe, e.g. `v1.2.0` or `v1.2.0-3-g1a2b3c4`,
+// or the abbreviated hash of HEAD if there are no tags.
+func GitDescribe(dir string) (string, error) {
+	return git(dir, "describe", "--tags", "--always")
+}
+
+// GitRevision describes the checked out revision of a git repository.
+type GitRevision struct {
+	Commit string
+	// Branch is empty for a detached HEAD.
+	Branch string
+	// Dirty is true if tracked files have uncommitted changes.
+	Dirty bool
+}
+
+// GitHeadRevision returns the checked out revision of the git repository containing dir.
+func GitHeadRevision(dir string) (GitRevision, error) {
+	commit, err := git(dir, "rev-parse", "HEAD")
+	if err != nil {
+		return GitRevision{}, err
+	}
+	branch, err := git(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return GitRevision{}, err
+	}
+	if branch == "HEAD" {
+		branch = ""
+	}
+	status, err := git(dir, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return GitRevision{}, err
+	}
+
+	return GitRevision{Commit: commit, Branch: branch, Dirty: status != ""}, nil
+}
+
+// GitProjectName returns the name of the git repository containing dir,
+// taken from the url of the origin remote or else from the name of the repository directory.
+func GitProjectName(dir string) (string, error) {
+	if remote, err := git(dir, "remote", "get-url", "origin"); err == nil {
+		// handles urls like https://host/group/name.git as well as scp-like ones like git@host:group/name.git
+		name := path.Base(strings.TrimRight(remote, "/"))
+		name = name[strings.LastIndex(name, ":")+1:]
+		if name = strings.TrimSuffix(name, ".git"); name != "" && name != "." {
+			return name, nil
+		}
+	}
+
+	root, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Base(root), nil
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s failed: %s", strings.Join(args, " "), strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s failed: %w", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
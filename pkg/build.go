@@ -1,14 +1,26 @@
 package docatl
 
 import (
-	"archive/zip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
 
 	util "github.com/docat-org/docatl/internal"
+	"github.com/dsnet/compress/bzip2"
+	"github.com/klauspost/compress/flate"
+	"github.com/klauspost/compress/zip"
+	"github.com/klauspost/compress/zstd"
 	"github.com/mholt/archiver/v3"
 	"gopkg.in/yaml.v2"
 )
@@ -16,47 +28,414 @@ import (
 const metadataFileName = ".docatl.meta.yaml"
 
 type BuildMetadata struct {
-	Host    string `yaml:"host,omitempty"`
-	Project string `yaml:"project,omitempty"`
-	Version string `yaml:"version,omitempty"`
+	Host    string `yaml:"host,omitempty" json:"host,omitempty"`
+	Project string `yaml:"project,omitempty" json:"project,omitempty"`
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+	// Tags are applied to the version when the artifact is pushed.
+	Tags []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	// Labels are free-form key value pairs describing the documentation.
+	Labels map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	// Commit, Branch and Dirty describe the git revision the documentation was built from, if any.
+	Commit string `yaml:"commit,omitempty" json:"commit,omitempty"`
+	Branch string `yaml:"branch,omitempty" json:"branch,omitempty"`
+	Dirty  bool   `yaml:"dirty,omitempty" json:"dirty,omitempty"`
+	// BuildTime is set by Build, except for reproducible builds.
+	BuildTime time.Time `yaml:"build-time,omitempty" json:"build-time,omitzero"`
+	// DocatlVersion is the version of docatl which built the artifact, set by Build.
+	DocatlVersion string `yaml:"docatl-version,omitempty" json:"docatl-version,omitempty"`
+	// SourceChecksum is the SHA-256 checksum over the names and contents of all archived files, set by Build.
+	SourceChecksum string `yaml:"source-checksum,omitempty" json:"source-checksum,omitempty"`
+	// Checksums holds the SHA-256 checksum of every archived file by its name, set by Build.
+	Checksums map[string]string `yaml:"checksums,omitempty" json:"checksums,omitempty"`
 }
 
-func Build(docsPath string, meta BuildMetadata) (string, error) {
+const docatlModulePath = "github.com/docat-org/docatl"
+
+// docatlVersion returns the version of the docatl module from the build info of the binary.
+func docatlVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	if info.Main.Path == docatlModulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == docatlModulePath {
+			return dep.Version
+		}
+	}
+	return ""
+}
+
+const (
+	CompressionStore   = "store"
+	CompressionDeflate = "deflate"
+	CompressionBzip2   = "bzip2"
+	CompressionZstd    = "zstd"
+)
+
+// reproducibleModTime is the modification time of all archive entries in reproducible builds.
+var reproducibleModTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+type BuildOptions struct {
+	// Exclude holds gitignore-style patterns of files to leave out,
+	// in addition to the patterns of the .docatlignore file in the docs root.
+	Exclude []string
+	// Include holds gitignore-style patterns. When given, only matching files are archived.
+	Include []string
+	// Compression is the compression method of the archive entries, bzip2 if empty.
+	Compression string
+	// CompressionLevel is the level of the compression method, 0 uses its default.
+	CompressionLevel int
+	// Reproducible builds produce byte-identical archives for identical inputs
+	// by normalizing modification times and permissions of the archive entries.
+	Reproducible bool
+	// Output is the path of the artifact and may contain the placeholders {project}, {version} and {commit}.
+	// Defaults to docs_<project>_<version>.zip.
+	Output string
+	// OutputDir is the directory the artifact is written to, unless Output is an absolute path.
+	OutputDir string
+	// SigningKey signs the metadata of the artifact if given, which requires project and version.
+	SigningKey ed25519.PrivateKey
+}
+
+type archiveEntry struct {
+	name string
+	path string
+}
+
+func Build(docsPath string, meta BuildMetadata, opts BuildOptions) (string, error) {
 	if !util.IsDirectory(docsPath) {
 		return "", fmt.Errorf("the given documentation path must be a directory")
 	}
 
 	docsPath = util.ResolvePath(docsPath)
 
-	// NOTE(TF): the `archiver` package does not have an option to not create a top-level directory
-	filesInDocsPath, err := os.ReadDir(docsPath)
+	withMetadata := meta.Project != "" && meta.Version != ""
+	if opts.SigningKey != nil && !withMetadata {
+		return "", errors.New("signing the artifact requires project and version, as the signature covers its metadata")
+	}
+
+	outputPath, err := artifactPath(docsPath, meta, opts)
 	if err != nil {
-		return "", fmt.Errorf("cannot list the contents within the given documentation directory: %w", err)
+		return "", err
 	}
-	filesToArchive := make([]string, 0)
-	for _, f := range filesInDocsPath {
-		filesToArchive = append(filesToArchive, filepath.Join(docsPath, f.Name()))
+
+	entries, err := collectEntries(docsPath, opts, withMetadata, outputPath)
+	if err != nil {
+		return "", err
 	}
 
-	if meta.Project != "" && meta.Version != "" {
-		metadataFile, err := generateMetadataFile(meta)
+	var metadata *BuildMetadata
+	if withMetadata {
+		if !opts.Reproducible {
+			meta.BuildTime = time.Now().UTC().Truncate(time.Second)
+		}
+		meta.DocatlVersion = docatlVersion()
+		metadata = &meta
+	}
+
+	err = writeArchive(outputPath, entries, metadata, opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to archive docs: %w", err)
+	}
+
+	return outputPath, nil
+}
+
+// collectEntries lists the files to archive, leaving out the ones
+// excluded by the build options or the .docatlignore file.
+// The artifact and its checksum file are left out as well, in case they are written into the documentation directory.
+func collectEntries(docsPath string, opts BuildOptions, withMetadata bool, outputPath string) ([]archiveEntry, error) {
+	ignorePatterns, err := readIgnoreFile(docsPath)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := newIgnoreMatcher(append(ignorePatterns, opts.Exclude...))
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+	include, err := newIgnoreMatcher(opts.Include)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include pattern: %w", err)
+	}
+
+	entries := make([]archiveEntry, 0)
+	err = filepath.WalkDir(docsPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == docsPath {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(docsPath, path)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		filesToArchive = append(filesToArchive, metadataFile)
+		name := filepath.ToSlash(relPath)
+
+		if d.IsDir() {
+			if exclude.matches(name, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if name == ignoreFileName || (withMetadata && (name == metadataFileName || name == signatureFileName)) {
+			return nil
+		}
+		if IsArtifactFile(path, outputPath) {
+			return nil
+		}
+		if exclude.matches(name, false) {
+			return nil
+		}
+		if !include.empty() && !include.matchesWithParents(name) {
+			return nil
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 {
+			info, err := os.Stat(path)
+			if err != nil {
+				return fmt.Errorf("cannot resolve symlink '%s': %w", path, err)
+			}
+			if info.IsDir() {
+				log.Printf("skipping symlinked directory '%s'", path)
+				return nil
+			}
+		}
+
+		entries = append(entries, archiveEntry{name: name, path: path})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list the contents within the given documentation directory: %w", err)
 	}
 
-	outputPath := generateArtifactFileName(docsPath, meta)
+	return entries, nil
+}
 
-	z := archiver.Zip{OverwriteExisting: true,  FileMethod: archiver.BZIP2}
-	err = z.Archive(filesToArchive, outputPath)
+// writeArchive writes the entries into the archive, followed by the metadata if given.
+// The checksums of the source files are added to the metadata while archiving them,
+// the digest of the archive is written to the checksum file next to it.
+func writeArchive(outputPath string, entries []archiveEntry, meta *BuildMetadata, opts BuildOptions) error {
+	method, compressor, err := newCompressor(opts.Compression, opts.CompressionLevel)
 	if err != nil {
-		return "", fmt.Errorf("failed to archive docs: %w", err)
+		return err
 	}
 
+	out, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("unable to create '%s': %w", outputPath, err)
+	}
+	defer func() { _ = out.Close() }()
+
+	archiveDigest := sha256.New()
+	zw := zip.NewWriter(io.MultiWriter(out, archiveDigest))
+	if compressor != nil {
+		zw.RegisterCompressor(method, compressor)
+	}
+
+	slices.SortFunc(entries, func(a, b archiveEntry) int {
+		return strings.Compare(a.name, b.name)
+	})
+	sourceChecksum := sha256.New()
+	checksums := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		digest, err := writeArchiveEntry(zw, method, entry, opts.Reproducible)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(sourceChecksum, "%s\x00%x\n", entry.name, digest)
+		checksums[entry.name] = checksumPrefix + hex.EncodeToString(digest)
+	}
+
+	if meta != nil {
+		meta.SourceChecksum = checksumPrefix + hex.EncodeToString(sourceChecksum.Sum(nil))
+		meta.Checksums = checksums
+		metadata, err := generateMetadata(*meta)
+		if err != nil {
+			return err
+		}
+
+		if err = writeGeneratedEntry(zw, method, metadataFileName, metadata, opts.Reproducible); err != nil {
+			return fmt.Errorf("unable to add metadata to archive: %w", err)
+		}
+		if opts.SigningKey != nil {
+			if err = writeGeneratedEntry(zw, method, signatureFileName, signMetadata(opts.SigningKey, metadata), opts.Reproducible); err != nil {
+				return fmt.Errorf("unable to add signature to archive: %w", err)
+			}
+		}
+	}
+
+	if err = zw.Close(); err != nil {
+		return err
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+	return writeChecksumFile(outputPath, archiveDigest.Sum(nil))
+}
+
+// writeArchiveEntry adds the file to the archive and returns the SHA-256 digest of its contents.
+func writeArchiveEntry(zw *zip.Writer, method uint16, entry archiveEntry, reproducible bool) ([]byte, error) {
+	file, err := os.Open(entry.path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open '%s': %w", entry.path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("unable to stat '%s': %w", entry.path, err)
+	}
+
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create archive header for '%s': %w", entry.path, err)
+	}
+	header.Name = entry.name
+	header.Method = method
+	if reproducible {
+		header.Modified = reproducibleModTime
+		header.SetMode(0644)
+	}
+
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return nil, fmt.Errorf("unable to add '%s' to archive: %w", entry.name, err)
+	}
+	digest := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(w, digest), file); err != nil {
+		return nil, fmt.Errorf("unable to add '%s' to archive: %w", entry.name, err)
+	}
+	return digest.Sum(nil), nil
+}
+
+// writeGeneratedEntry adds a file generated by docatl, like the metadata, to the archive.
+func writeGeneratedEntry(zw *zip.Writer, method uint16, name string, contents []byte, reproducible bool) error {
+	header := &zip.FileHeader{Name: name, Method: method, Modified: time.Now()}
+	if reproducible {
+		header.Modified = reproducibleModTime
+	}
+	header.SetMode(0644)
+
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(contents)
+	return err
+}
+
+// newCompressor returns the zip method id and compressor for the named compression method.
+// The compressor is nil for methods the zip writer supports out of the box.
+func newCompressor(compression string, level int) (uint16, zip.Compressor, error) {
+	switch compression {
+	case CompressionStore:
+		return zip.Store, nil, nil
+	case CompressionDeflate:
+		if level == 0 {
+			level = flate.DefaultCompression
+		}
+		if level < flate.HuffmanOnly || level > flate.BestCompression {
+			return 0, nil, fmt.Errorf("invalid deflate compression level %d, must be between 1 and 9", level)
+		}
+		return zip.Deflate, func(w io.Writer) (io.WriteCloser, error) {
+			return flate.NewWriter(w, level)
+		}, nil
+	case "", CompressionBzip2:
+		if level < 0 || level > bzip2.BestCompression {
+			return 0, nil, fmt.Errorf("invalid bzip2 compression level %d, must be between 1 and 9", level)
+		}
+		return uint16(archiver.BZIP2), func(w io.Writer) (io.WriteCloser, error) {
+			return bzip2.NewWriter(w, &bzip2.WriterConfig{Level: level})
+		}, nil
+	case CompressionZstd:
+		encoderLevel := zstd.SpeedDefault
+		if level != 0 {
+			encoderLevel = zstd.EncoderLevelFromZstd(level)
+		}
+		return uint16(archiver.ZSTD), func(w io.Writer) (io.WriteCloser, error) {
+			return zstd.NewWriter(w, zstd.WithEncoderLevel(encoderLevel))
+		}, nil
+	default:
+		return 0, nil, fmt.Errorf("unknown compression '%s', must be one of: %s, %s, %s, %s", compression, CompressionStore, CompressionDeflate, CompressionBzip2, CompressionZstd)
+	}
+}
+
+// openArtifact opens the artifact for reading, with support for all compression methods of Build.
+func openArtifact(artifactPath string) (*zip.ReadCloser, error) {
+	zr, err := zip.OpenReader(artifactPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open artifact '%s': %w", artifactPath, err)
+	}
+	zr.RegisterDecompressor(uint16(archiver.BZIP2), func(r io.Reader) io.ReadCloser {
+		br, err := bzip2.NewReader(r, nil)
+		if err != nil {
+			return errReader{err}
+		}
+		return br
+	})
+	zr.RegisterDecompressor(uint16(archiver.ZSTD), func(r io.Reader) io.ReadCloser {
+		zd, err := zstd.NewReader(r)
+		if err != nil {
+			return errReader{err}
+		}
+		return zd.IOReadCloser()
+	})
+	return zr, nil
+}
+
+// errReader fails every read with its error, for decompressors which can't be created.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+func (r errReader) Close() error { return nil }
+
+// IsArtifactFile reports whether path is the artifact or its checksum file.
+func IsArtifactFile(path string, artifactPath string) bool {
+	path, artifactPath = util.ResolvePath(path), util.ResolvePath(artifactPath)
+	return path == artifactPath || path == artifactPath+ChecksumFileSuffix
+}
+
+func artifactPath(docsPath string, meta BuildMetadata, opts BuildOptions) (string, error) {
+	outputPath := generateArtifactFileName(docsPath, meta)
+	if opts.Output != "" {
+		var err error
+		outputPath, err = expandOutputTemplate(opts.Output, docsPath, meta)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if opts.OutputDir != "" && !filepath.IsAbs(outputPath) {
+		outputPath = filepath.Join(opts.OutputDir, outputPath)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return "", fmt.Errorf("unable to create output directory for '%s': %w", outputPath, err)
+	}
 	return outputPath, nil
 }
 
+func expandOutputTemplate(template string, docsPath string, meta BuildMetadata) (string, error) {
+	replacements := []string{"{project}", meta.Project, "{version}", meta.Version}
+	if strings.Contains(template, "{commit}") {
+		commit, err := util.GitShortCommit(docsPath)
+		if err != nil {
+			return "", fmt.Errorf("unable to resolve {commit} in output path: %w", err)
+		}
+		replacements = append(replacements, "{commit}", commit)
+	}
+	return strings.NewReplacer(replacements...).Replace(template), nil
+}
+
 func generateArtifactFileName(docsPath string, meta BuildMetadata) string {
 	if meta.Project == "" && meta.Version == "" {
 		return fmt.Sprintf("%s.zip", filepath.Base(docsPath))
@@ -69,24 +448,28 @@ func generateArtifactFileName(docsPath string, meta BuildMetadata) string {
 	return fmt.Sprintf("docs_%s_%s.zip", meta.Project, meta.Version)
 }
 
-func generateMetadataFile(meta BuildMetadata) (string, error) {
-	tmpDir, err := os.MkdirTemp("", "docatl-*")
-	if err != nil {
-		return "", fmt.Errorf("unable to create temp directory for metadatafile: %w", err)
-	}
-	metadataFile := filepath.Join(tmpDir, metadataFileName)
-
+func generateMetadata(meta BuildMetadata) ([]byte, error) {
 	doc, err := yaml.Marshal(&meta)
 	if err != nil {
-		return "", fmt.Errorf("unable to generate metadata file for data: %v: %w", meta, err)
+		return nil, fmt.Errorf("unable to generate metadata file for data: %v: %w", meta, err)
 	}
+	return doc, nil
+}
 
-	err = os.WriteFile(metadataFile, doc, 0755)
-	if err != nil {
-		return "", fmt.Errorf("unabel to write metadata to file %s: %w", metadataFile, err)
+// Unpack extracts the documentation of an artifact into destPath, leaving out the docatl metadata.
+func Unpack(artifactPath string, destPath string) error {
+	z := archiver.Zip{OverwriteExisting: true, MkdirAll: true}
+	if err := z.Unarchive(artifactPath, destPath); err != nil {
+		return fmt.Errorf("unable to unpack artifact '%s': %w", artifactPath, err)
 	}
 
-	return metadataFile, nil
+	for _, name := range []string{metadataFileName, signatureFileName} {
+		err := os.Remove(filepath.Join(destPath, name))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("unable to remove metadata from unpacked artifact: %w", err)
+		}
+	}
+	return nil
 }
 
 func ExtractMetadata(docsPath string) (BuildMetadata, error) {
@@ -115,3 +498,34 @@ func ExtractMetadata(docsPath string) (BuildMetadata, error) {
 
 	return meta, nil
 }
+
+// ArtifactFile describes a file in a documentation artifact.
+type ArtifactFile struct {
+	Name           string    `json:"name" yaml:"name"`
+	Size           uint64    `json:"size" yaml:"size"`
+	CompressedSize uint64    `json:"compressed-size" yaml:"compressed-size"`
+	Modified       time.Time `json:"modified" yaml:"modified"`
+}
+
+// ListArtifactFiles lists all files in the documentation artifact, including its metadata.
+func ListArtifactFiles(artifactPath string) ([]ArtifactFile, error) {
+	zr, err := openArtifact(artifactPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = zr.Close() }()
+
+	var files []ArtifactFile
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		files = append(files, ArtifactFile{
+			Name:           f.Name,
+			Size:           f.UncompressedSize64,
+			CompressedSize: f.CompressedSize64,
+			Modified:       f.Modified,
+		})
+	}
+	return files, nil
+}
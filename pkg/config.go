@@ -1,15 +1,84 @@
 package docatl
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
 	"os"
+	"slices"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Host   string `yaml:"host"`
-	ApiKey string `yaml:"api-key"`
+	Host   string `yaml:"host,omitempty"`
+	ApiKey string `yaml:"api-key,omitempty"`
+	// ApiKeys holds the api keys of single projects, which take precedence over ApiKey.
+	ApiKeys map[string]string `yaml:"api-keys,omitempty"`
+	// Profile is the name of the profile used, unless another one is selected with --profile or DOCATL_PROFILE.
+	Profile  string             `yaml:"profile,omitempty"`
+	Profiles map[string]Profile `yaml:"profiles,omitempty"`
+	// Credentials selects where api keys are stored.
+	Credentials CredentialConfig `yaml:"credentials,omitempty"`
+	// Extra holds all other settings of the config file, so they are kept when the config is written.
+	Extra map[string]any `yaml:",inline"`
+}
+
+// Profile holds the settings for one docat server, which take precedence over
+// the top level settings of the config when the profile is used.
+type Profile struct {
+	Host   string `yaml:"host,omitempty"`
+	ApiKey string `yaml:"api-key,omitempty"`
+	// ApiKeys holds the api keys of single projects, which take precedence over ApiKey.
+	ApiKeys map[string]string `yaml:"api-keys,omitempty"`
+	// Defaults holds defaults for any other setting or flag, e.g. `retries: 5`.
+	Defaults map[string]any `yaml:",inline"`
+}
+
+// Settings returns all settings of the profile by their config key, which are merged over the top level settings.
+// A profile for another host than the top level one must not inherit the top level api keys, so they are cleared.
+func (profile Profile) Settings(config Config) map[string]any {
+	settings := maps.Clone(profile.Defaults)
+	if settings == nil {
+		settings = map[string]any{}
+	}
+	if profile.Host != "" {
+		settings["host"] = profile.Host
+		if !sameHost(profile.Host, config.Host) {
+			// merging can't remove settings, so the api keys are overridden with empty ones
+			settings["api-key"] = ""
+			apiKeys := map[string]any{}
+			for project := range config.ApiKeys {
+				apiKeys[project] = ""
+			}
+			settings["api-keys"] = apiKeys
+		}
+	}
+	if profile.ApiKey != "" {
+		settings["api-key"] = profile.ApiKey
+	}
+	return settings
+}
+
+// ReadConfig reads the config file, a missing config file results in an empty config.
+func ReadConfig(configPath string) (Config, error) {
+	var config Config
+
+	doc, err := os.ReadFile(configPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("unable to read config from '%s': %w", configPath, err)
+	}
+
+	if err = yaml.Unmarshal(doc, &config); err != nil {
+		return config, fmt.Errorf("unable to parse config '%s': %w", configPath, err)
+	}
+
+	return config, nil
 }
 
 func WriteConfig(configPath string, config Config) error {
@@ -18,10 +87,222 @@ func WriteConfig(configPath string, config Config) error {
 		return fmt.Errorf("unable to marshal config '%v' to YAML: %w", config, err)
 	}
 
-	err = os.WriteFile(configPath, doc, 0644)
-	if err != nil {
+	// the config contains api keys
+	if err = writePrivateFile(configPath, doc); err != nil {
 		return fmt.Errorf("unable to write config to '%s': %w", configPath, err)
 	}
 
 	return nil
 }
+
+// writePrivateFile writes the file only readable by its owner. Unlike os.WriteFile, it restricts
+// the permissions of existing files as well, before the data is written to them.
+func writePrivateFile(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	if err = file.Chmod(0600); err != nil {
+		return err
+	}
+	if _, err = file.Write(data); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// Validate checks that the selected profile exists.
+func (config Config) Validate() error {
+	if _, ok := config.Profiles[config.Profile]; config.Profile != "" && !ok {
+		return fmt.Errorf("profile '%s' is selected, but does not exist", config.Profile)
+	}
+	return nil
+}
+
+// Get returns the value of the setting with the given key,
+// where the keys of nested settings are separated by dots, e.g. `profiles.public.host`.
+func (config Config) Get(key string) (any, bool, error) {
+	path, err := splitKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+	root, err := config.node()
+	if err != nil {
+		return nil, false, err
+	}
+
+	node := root
+	for _, name := range path {
+		if node = mappingValue(node, name); node == nil {
+			return nil, false, nil
+		}
+	}
+
+	var value any
+	if err = node.Decode(&value); err != nil {
+		return nil, false, fmt.Errorf("unable to read setting '%s': %w", key, err)
+	}
+	return value, true, nil
+}
+
+// Set sets the setting with the given key, creating nested settings as needed.
+// The value is parsed as YAML, so lists like `[latest, stable]` can be set as well.
+func (config *Config) Set(key string, value string) error {
+	path, err := splitKey(key)
+	if err != nil {
+		return err
+	}
+	root, err := config.node()
+	if err != nil {
+		return err
+	}
+
+	parent := root
+	for _, name := range path[:len(path)-1] {
+		child := mappingValue(parent, name)
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}, child)
+		} else if child.Kind != yaml.MappingNode {
+			return fmt.Errorf("unable to set '%s' because '%s' is not a section", key, name)
+		}
+		parent = child
+	}
+
+	name := path[len(path)-1]
+	if existing := mappingValue(parent, name); existing != nil {
+		*existing = *valueNode(value)
+	} else {
+		parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}, valueNode(value))
+	}
+
+	if err = config.decode(root); err != nil {
+		return fmt.Errorf("unable to set '%s': %w", key, err)
+	}
+	return nil
+}
+
+// Unset removes the setting with the given key and reports whether it was set.
+func (config *Config) Unset(key string) (bool, error) {
+	path, err := splitKey(key)
+	if err != nil {
+		return false, err
+	}
+	root, err := config.node()
+	if err != nil {
+		return false, err
+	}
+
+	parent := root
+	for _, name := range path[:len(path)-1] {
+		if parent = mappingValue(parent, name); parent == nil {
+			return false, nil
+		}
+	}
+
+	name := path[len(path)-1]
+	for i := 0; parent.Kind == yaml.MappingNode && i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == name {
+			parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+			if err = config.decode(root); err != nil {
+				return false, fmt.Errorf("unable to unset '%s': %w", key, err)
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Masked returns a copy of the config where secrets, like api keys, are masked.
+func (config Config) Masked() (Config, error) {
+	root, err := config.node()
+	if err != nil {
+		return Config{}, err
+	}
+	maskSecrets(root, false)
+
+	var masked Config
+	if err = masked.decode(root); err != nil {
+		return Config{}, err
+	}
+	return masked, nil
+}
+
+func (config Config) node() (*yaml.Node, error) {
+	var node yaml.Node
+	if err := node.Encode(config); err != nil {
+		return nil, fmt.Errorf("unable to encode config: %w", err)
+	}
+	return &node, nil
+}
+
+func (config *Config) decode(node *yaml.Node) error {
+	var decoded Config
+	if err := node.Decode(&decoded); err != nil {
+		return err
+	}
+	*config = decoded
+	return nil
+}
+
+func splitKey(key string) ([]string, error) {
+	path := strings.Split(key, ".")
+	if slices.Contains(path, "") {
+		return nil, fmt.Errorf("invalid setting key '%s'", key)
+	}
+	return path, nil
+}
+
+// mappingValue returns the value of the key in the mapping node, or nil if there is none.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// valueNode parses the value as YAML, falling back to a plain string.
+func valueNode(value string) *yaml.Node {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &doc); err == nil && len(doc.Content) == 1 {
+		return doc.Content[0]
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+const maskedSecret = "********"
+
+// maskSecrets masks all values of secret settings below the node.
+func maskSecrets(node *yaml.Node, secret bool) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if secret && node.Value != "" {
+			*node = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: maskedSecret}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			maskSecrets(node.Content[i+1], secret || isSecretKey(node.Content[i].Value))
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			maskSecrets(item, secret)
+		}
+	}
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range []string{"api-key", "token", "password", "secret"} {
+		if strings.Contains(key, secret) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,375 @@
+package docatl
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// credential stores
+const (
+	CredentialStoreFile          = "file"
+	CredentialStoreEncryptedFile = "encrypted-file"
+	CredentialStoreHelper        = "helper"
+)
+
+// CredentialStore stores the api keys for docat servers.
+// The project may be empty for an api key used for all projects of the server.
+type CredentialStore interface {
+	// Get returns the api key for the project on the docat host, ok is false if there is none.
+	Get(host string, project string) (apiKey string, ok bool, err error)
+	// Store stores the api key for the project on the docat host.
+	Store(host string, project string, apiKey string) error
+	// Erase removes the api key for the project on the docat host, if there is one.
+	Erase(host string, project string) error
+}
+
+// CredentialConfig selects the credential store in the config file.
+type CredentialConfig struct {
+	// Store is one of file (default), encrypted-file or helper.
+	Store string `yaml:"store,omitempty"`
+	// Path is the path of the encrypted file, defaults to `credentials.enc` in the user config directory.
+	Path string `yaml:"path,omitempty"`
+	// Helper is the command of a git-credential style helper, e.g. `git credential-osxkeychain`.
+	Helper string `yaml:"helper,omitempty"`
+}
+
+// FileCredentialStore stores the api keys in plaintext in the config file, in the profile if one is given.
+// Api keys of projects are stored in the `api-keys` map, the api key for all projects in `api-key`.
+// The api keys are only returned for the host of the config or the profile, api keys of projects which the
+// profile has no entry for are looked up in the top level `api-keys` if the profile uses the top level host.
+type FileCredentialStore struct {
+	ConfigPath string
+	Profile    string
+}
+
+func (store FileCredentialStore) Get(host string, project string) (string, bool, error) {
+	config, err := ReadConfig(store.ConfigPath)
+	if err != nil {
+		return "", false, err
+	}
+
+	apiKey, apiKeys, entryHost := config.ApiKey, config.ApiKeys, config.Host
+	fallbackApiKeys := map[string]string{}
+	if store.Profile != "" {
+		profile := config.Profiles[store.Profile]
+		apiKey, apiKeys = profile.ApiKey, profile.ApiKeys
+		if profile.Host == "" || sameHost(profile.Host, config.Host) {
+			fallbackApiKeys = config.ApiKeys
+		} else {
+			entryHost = profile.Host
+		}
+	}
+	if host != "" && entryHost != "" && !sameHost(host, entryHost) {
+		return "", false, nil
+	}
+
+	if project != "" {
+		var ok bool
+		if apiKey, ok = apiKeys[project]; !ok {
+			apiKey = fallbackApiKeys[project]
+		}
+	}
+	return apiKey, apiKey != "", nil
+}
+
+func (store FileCredentialStore) Store(host string, project string, apiKey string) error {
+	return store.update(func(config *Config) {
+		if store.Profile != "" {
+			profile := config.Profiles[store.Profile]
+			if profile.Host == "" {
+				profile.Host = host
+			}
+			setApiKey(&profile.ApiKey, &profile.ApiKeys, project, apiKey)
+			if config.Profiles == nil {
+				config.Profiles = map[string]Profile{}
+			}
+			config.Profiles[store.Profile] = profile
+			return
+		}
+		if config.Host == "" {
+			config.Host = host
+		}
+		setApiKey(&config.ApiKey, &config.ApiKeys, project, apiKey)
+	})
+}
+
+func (store FileCredentialStore) Erase(host string, project string) error {
+	return store.update(func(config *Config) {
+		if store.Profile != "" {
+			if profile, ok := config.Profiles[store.Profile]; ok {
+				setApiKey(&profile.ApiKey, &profile.ApiKeys, project, "")
+				config.Profiles[store.Profile] = profile
+			}
+			return
+		}
+		setApiKey(&config.ApiKey, &config.ApiKeys, project, "")
+	})
+}
+
+// sameHost returns whether both urls point to the same docat host, ignoring trailing slashes.
+func sameHost(a string, b string) bool {
+	return strings.EqualFold(strings.TrimRight(a, "/"), strings.TrimRight(b, "/"))
+}
+
+// setApiKey sets the api key of the project, or the api key for all projects if project is empty.
+// An empty api key removes it.
+func setApiKey(globalApiKey *string, apiKeys *map[string]string, project string, apiKey string) {
+	switch {
+	case project == "":
+		*globalApiKey = apiKey
+	case apiKey == "":
+		delete(*apiKeys, project)
+	default:
+		if *apiKeys == nil {
+			*apiKeys = map[string]string{}
+		}
+		(*apiKeys)[project] = apiKey
+	}
+}
+
+func (store FileCredentialStore) update(change func(config *Config)) error {
+	config, err := ReadConfig(store.ConfigPath)
+	if err != nil {
+		return err
+	}
+	change(&config)
+	return WriteConfig(store.ConfigPath, config)
+}
+
+// EncryptedFileCredentialStore stores the api keys in a file encrypted with AES-256-GCM,
+// using a key derived from the passphrase with PBKDF2.
+type EncryptedFileCredentialStore struct {
+	Path       string
+	Passphrase string
+}
+
+const (
+	encryptedCredentialsHeader = "docatl-credentials-v1"
+	credentialKeyIterations    = 600_000
+	credentialSaltSize         = 16
+)
+
+type storedCredential struct {
+	Host    string `json:"host"`
+	Project string `json:"project,omitempty"`
+	ApiKey  string `json:"api-key"`
+}
+
+// DefaultEncryptedCredentialsPath returns the path of the encrypted credentials in the user config directory.
+func DefaultEncryptedCredentialsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find user config directory: %w", err)
+	}
+	return filepath.Join(dir, "docatl", "credentials.enc"), nil
+}
+
+func (store EncryptedFileCredentialStore) Get(host string, project string) (string, bool, error) {
+	credentials, err := store.read()
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, credential := range credentials {
+		if credential.Host == host && credential.Project == project {
+			return credential.ApiKey, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+func (store EncryptedFileCredentialStore) Store(host string, project string, apiKey string) error {
+	credentials, err := store.read()
+	if err != nil {
+		return err
+	}
+
+	credentials = slices.DeleteFunc(credentials, func(credential storedCredential) bool {
+		return credential.Host == host && credential.Project == project
+	})
+	credentials = append(credentials, storedCredential{Host: host, Project: project, ApiKey: apiKey})
+	return store.write(credentials)
+}
+
+func (store EncryptedFileCredentialStore) Erase(host string, project string) error {
+	credentials, err := store.read()
+	if err != nil {
+		return err
+	}
+
+	credentials = slices.DeleteFunc(credentials, func(credential storedCredential) bool {
+		return credential.Host == host && credential.Project == project
+	})
+	return store.write(credentials)
+}
+
+func (store EncryptedFileCredentialStore) read() ([]storedCredential, error) {
+	data, err := os.ReadFile(store.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read credentials from '%s': %w", store.Path, err)
+	}
+
+	header := []byte(encryptedCredentialsHeader)
+	if !bytes.HasPrefix(data, header) {
+		return nil, fmt.Errorf("unable to read credentials because '%s' is not an encrypted credentials file", store.Path)
+	}
+	data = data[len(header):]
+	if len(data) < credentialSaltSize {
+		return nil, fmt.Errorf("unable to read credentials because '%s' is truncated", store.Path)
+	}
+
+	gcm, err := store.cipher(data[:credentialSaltSize])
+	if err != nil {
+		return nil, err
+	}
+	data = data[credentialSaltSize:]
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("unable to read credentials because '%s' is truncated", store.Path)
+	}
+
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], header)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt credentials from '%s', the passphrase may be wrong: %w", store.Path, err)
+	}
+
+	var credentials []storedCredential
+	if err = json.Unmarshal(plaintext, &credentials); err != nil {
+		return nil, fmt.Errorf("unable to parse credentials from '%s': %w", store.Path, err)
+	}
+	return credentials, nil
+}
+
+func (store EncryptedFileCredentialStore) write(credentials []storedCredential) error {
+	plaintext, err := json.Marshal(credentials)
+	if err != nil {
+		return fmt.Errorf("unable to marshal credentials: %w", err)
+	}
+
+	salt := make([]byte, credentialSaltSize)
+	if _, err = rand.Read(salt); err != nil {
+		return fmt.Errorf("unable to generate salt: %w", err)
+	}
+	gcm, err := store.cipher(salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return fmt.Errorf("unable to generate nonce: %w", err)
+	}
+
+	header := []byte(encryptedCredentialsHeader)
+	data := slices.Concat(header, salt, nonce, gcm.Seal(nil, nonce, plaintext, header))
+
+	if err = os.MkdirAll(filepath.Dir(store.Path), 0700); err != nil {
+		return fmt.Errorf("unable to create directory for credentials: %w", err)
+	}
+	if err = writePrivateFile(store.Path, data); err != nil {
+		return fmt.Errorf("unable to write credentials to '%s': %w", store.Path, err)
+	}
+	return nil
+}
+
+func (store EncryptedFileCredentialStore) cipher(salt []byte) (cipher.AEAD, error) {
+	if store.Passphrase == "" {
+		return nil, errors.New("unable to access encrypted credentials because no passphrase is given")
+	}
+
+	key, err := pbkdf2.Key(sha256.New, store.Passphrase, salt, credentialKeyIterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive key from passphrase: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// HelperCredentialStore stores the api keys with a credential helper using the protocol of git credential helpers,
+// so existing helpers for the secret store of the OS can be used, e.g. `git credential-osxkeychain`.
+// The helper is called with the action (get, store or erase) as additional argument
+// and the protocol, host, path (the project) and username (docatl) on stdin.
+type HelperCredentialStore struct {
+	Command string
+}
+
+const credentialHelperUsername = "docatl"
+
+func (store HelperCredentialStore) Get(host string, project string) (string, bool, error) {
+	out, err := store.run("get", host, project, "")
+	if err != nil {
+		return "", false, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if apiKey, ok := strings.CutPrefix(scanner.Text(), "password="); ok && apiKey != "" {
+			return apiKey, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+func (store HelperCredentialStore) Store(host string, project string, apiKey string) error {
+	_, err := store.run("store", host, project, apiKey)
+	return err
+}
+
+func (store HelperCredentialStore) Erase(host string, project string) error {
+	_, err := store.run("erase", host, project, "")
+	return err
+}
+
+func (store HelperCredentialStore) run(action string, host string, project string, apiKey string) ([]byte, error) {
+	args := strings.Fields(store.Command)
+	if len(args) == 0 {
+		return nil, errors.New("unable to use credential helper because no helper command is configured")
+	}
+
+	hostUrl, err := url.Parse(host)
+	if err != nil || hostUrl.Host == "" {
+		return nil, fmt.Errorf("unable to use credential helper because host '%s' is not a valid url", host)
+	}
+
+	var input bytes.Buffer
+	_, _ = fmt.Fprintf(&input, "protocol=%s\nhost=%s\n", hostUrl.Scheme, hostUrl.Host)
+	if project != "" {
+		_, _ = fmt.Fprintf(&input, "path=%s\n", project)
+	}
+	_, _ = fmt.Fprintf(&input, "username=%s\n", credentialHelperUsername)
+	if apiKey != "" {
+		_, _ = fmt.Fprintf(&input, "password=%s\n", apiKey)
+	}
+	input.WriteString("\n")
+
+	cmd := exec.Command(args[0], append(args[1:], action)...)
+	cmd.Stdin = &input
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("credential helper '%s %s' failed: %s", store.Command, action, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("credential helper '%s %s' failed: %w", store.Command, action, err)
+	}
+	return out, nil
+}
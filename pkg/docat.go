@@ -1,128 +1,248 @@
 package docatl
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
+	"mime"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
 )
 
 type Docat struct {
 	Host   string
 	ApiKey string
+	// Client sends the requests to docat, http.DefaultClient is used if nil.
+	// Use the Transport of the client to provide a custom http.RoundTripper.
+	Client *http.Client
+	// Retry configures retries of requests failing with transient errors.
+	Retry RetryPolicy
+	// Progress is called while files are uploaded, if set.
+	Progress ProgressFunc
 }
 
 type ProjectClaim struct {
 	Token string
 }
 
-func (docat *Docat) Post(project string, version string, docsPath string) error {
-	file, err := os.Open(docsPath)
-	if err != nil {
-		return fmt.Errorf("unable to upload documentation because it isn't accessible locally at '%s'", docsPath)
+type Project struct {
+	Name     string           `json:"name"`
+	Logo     bool             `json:"logo"`
+	Storage  string           `json:"storage"`
+	Versions []ProjectVersion `json:"versions"`
+}
+
+type ProjectVersion struct {
+	Name      string    `json:"name"`
+	Tags      []string  `json:"tags"`
+	Hidden    bool      `json:"hidden"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ResolveVersion finds the version of the project with the given name or tag.
+func (project Project) ResolveVersion(nameOrTag string) (ProjectVersion, bool) {
+	for _, version := range project.Versions {
+		if version.Name == nameOrTag {
+			return version, true
+		}
 	}
-	defer func() { _ = file.Close() }()
+	for _, version := range project.Versions {
+		if slices.Contains(version.Tags, nameOrTag) {
+			return version, true
+		}
+	}
+	return ProjectVersion{}, false
+}
 
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	part, err := writer.CreateFormFile("file", filepath.Base(file.Name()))
-	if err != nil {
-		return fmt.Errorf("unable to upload documentation because cannot create form file for file '%s'", docsPath)
+// UnmarshalJSON accepts the timestamps docat sends, which may come without a timezone.
+func (version *ProjectVersion) UnmarshalJSON(data []byte) error {
+	type projectVersion ProjectVersion
+	raw := struct {
+		*projectVersion
+		Timestamp string `json:"timestamp"`
+	}{projectVersion: (*projectVersion)(version)}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
 	}
-	_, err = io.Copy(part, file)
-	if err != nil {
-		return fmt.Errorf("unable to upload documentation because cannot copy file content of file '%s' to request", docsPath)
+
+	if raw.Timestamp == "" {
+		return nil
 	}
-	if err = writer.Close(); err != nil {
-		return fmt.Errorf("unable to upload documentation because cannot close multipart writer: %s", err)
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		if timestamp, err := time.Parse(layout, raw.Timestamp); err == nil {
+			version.Timestamp = timestamp
+			return nil
+		}
 	}
+	return fmt.Errorf("unable to parse timestamp '%s' of version %s", raw.Timestamp, version.Name)
+}
 
+func (docat *Docat) Post(project string, version string, docsPath string) error {
+	return docat.PostContext(context.Background(), project, version, docsPath)
+}
+
+func (docat *Docat) PostContext(ctx context.Context, project string, version string, docsPath string) error {
 	apiUrl, err := url.JoinPath(docat.Host, "api", project, version)
 	if err != nil {
-		return fmt.Errorf("unable to upload documentation because cannot create an url for host: %s error: %s", docat.Host, err)
+		return fmt.Errorf("unable to upload documentation because cannot create an url for host: %s error: %w", docat.Host, err)
 	}
 
-	request, err := http.NewRequest(http.MethodPost, apiUrl, body)
+	request, err := docat.newUploadRequest(ctx, apiUrl, docsPath)
 	if err != nil {
-		return fmt.Errorf("unable to upload documentation because cannot create POST request: %s", err)
+		return fmt.Errorf("unable to upload documentation because %w", err)
 	}
-	request.Header.Add("Content-Type", writer.FormDataContentType())
 	if docat.ApiKey != "" {
 		request.Header.Add("Docat-Api-Key", docat.ApiKey)
 	}
 
-	client := &http.Client{}
-	response, err := client.Do(request)
+	response, err := docat.do(request)
 	if err != nil {
-		return fmt.Errorf("unable to upload documentation: %s", err)
+		return fmt.Errorf("unable to upload documentation: %w", err)
 	}
 	defer func() { _ = response.Body.Close() }()
 
 	if response.StatusCode != http.StatusCreated {
-		bodyBytes, err := io.ReadAll(response.Body)
-		if err != nil {
-			return fmt.Errorf("unable to upload documentation and read it's response (status code: %d", response.StatusCode)
-		}
-		return fmt.Errorf("unable to upload documentation: (status code: %d) %s", response.StatusCode, string(bodyBytes))
+		return newAPIError("upload documentation", request, response)
 	}
 
 	return nil
 }
 
 func (docat *Docat) Delete(project string, version string) error {
+	return docat.DeleteContext(context.Background(), project, version)
+}
+
+func (docat *Docat) DeleteContext(ctx context.Context, project string, version string) error {
 	apiUrl, err := url.JoinPath(docat.Host, "api", project, version)
 	if err != nil {
-		return fmt.Errorf("unable to delete documentation because cannot create an url for host: %s error: %s", docat.Host, err)
+		return fmt.Errorf("unable to delete documentation because cannot create an url for host: %s error: %w", docat.Host, err)
 	}
 
-	request, err := http.NewRequest(http.MethodDelete, apiUrl, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, apiUrl, nil)
 	if err != nil {
-		return fmt.Errorf("unable to delete documentation because cannot create DELETE request: %s", err)
+		return fmt.Errorf("unable to delete documentation because cannot create DELETE request: %w", err)
 	}
 	request.Header.Add("Docat-Api-Key", docat.ApiKey)
 
-	client := &http.Client{}
-	response, err := client.Do(request)
+	response, err := docat.do(request)
 	if err != nil {
-		return fmt.Errorf("unable to delete documentation because request failed: %s", err)
+		return fmt.Errorf("unable to delete documentation because request failed: %w", err)
 	}
 	defer func() { _ = response.Body.Close() }()
 
 	if response.StatusCode != http.StatusOK {
-		bodyBytes, err := io.ReadAll(response.Body)
-		if err != nil {
-			return fmt.Errorf("unable to delete documentation and read it's response (status code: %d", response.StatusCode)
+		return newAPIError("delete documentation", request, response)
+	}
+
+	return nil
+}
+
+func (docat *Docat) Replace(project string, version string, docsPath string) (ProjectVersion, error) {
+	return docat.ReplaceContext(context.Background(), project, version, docsPath)
+}
+
+// ReplaceContext uploads the documentation in place of an existing version and returns the replaced version.
+// The existing version is deleted before the upload, and its tags and visibility are applied to the new upload afterwards.
+// It is downloaded first, so it can be restored if the upload fails.
+func (docat *Docat) ReplaceContext(ctx context.Context, project string, version string, docsPath string) (ProjectVersion, error) {
+	details, err := docat.GetProjectContext(ctx, project, true)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return ProjectVersion{}, fmt.Errorf("unable to replace documentation: %w", err)
+	}
+
+	index := slices.IndexFunc(details.Versions, func(existing ProjectVersion) bool { return existing.Name == version })
+	if index < 0 {
+		return ProjectVersion{}, docat.PostContext(ctx, project, version, docsPath)
+	}
+	replaced := details.Versions[index]
+
+	backupDir, err := os.MkdirTemp("", "docatl-replace-*")
+	if err != nil {
+		return replaced, fmt.Errorf("unable to replace documentation because cannot create temp directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(backupDir) }()
+	backupPath := filepath.Join(backupDir, "docs.zip")
+	if err = docat.DownloadContext(ctx, project, version, backupPath); err != nil {
+		return replaced, fmt.Errorf("unable to replace documentation because the existing version could not be backed up: %w", err)
+	}
+
+	if err = docat.DeleteContext(ctx, project, version); err != nil {
+		return replaced, fmt.Errorf("unable to replace documentation: %w", err)
+	}
+	if err = docat.PostContext(ctx, project, version, docsPath); err != nil {
+		if restoreErr := docat.restoreVersion(ctx, project, replaced, backupPath); restoreErr != nil {
+			return replaced, fmt.Errorf("unable to replace documentation because the upload failed: %w, and restoring the existing version failed: %w", err, restoreErr)
 		}
-		return fmt.Errorf("unable to delete documentation: (status code: %d) %s", response.StatusCode, string(bodyBytes))
+		return replaced, fmt.Errorf("unable to replace documentation because the upload failed, the existing version was restored: %w", err)
+	}
+
+	if err = docat.applyVersionState(ctx, project, replaced); err != nil {
+		return replaced, fmt.Errorf("unable to replace documentation: %w", err)
+	}
+	return replaced, nil
+}
+
+// restoreVersion uploads the backup of a deleted version again and applies its tags and visibility.
+func (docat *Docat) restoreVersion(ctx context.Context, project string, backup ProjectVersion, backupPath string) error {
+	// the restore must not be cancelled together with the failed upload
+	ctx = context.WithoutCancel(ctx)
+	if err := docat.PostContext(ctx, project, backup.Name, backupPath); err != nil {
+		return err
 	}
+	return docat.applyVersionState(ctx, project, backup)
+}
 
+// applyVersionState applies the tags and visibility of the given version to the uploaded version of the same name.
+func (docat *Docat) applyVersionState(ctx context.Context, project string, state ProjectVersion) error {
+	for _, tag := range state.Tags {
+		if err := docat.TagContext(ctx, project, state.Name, tag); err != nil {
+			return fmt.Errorf("the tags could not be applied again: %w", err)
+		}
+	}
+	if state.Hidden {
+		if err := docat.HideOrShowVersionContext(ctx, project, state.Name, true); err != nil {
+			return fmt.Errorf("the version could not be hidden again: %w", err)
+		}
+	}
 	return nil
 }
 
 func (docat *Docat) Claim(project string) (ProjectClaim, error) {
+	return docat.ClaimContext(context.Background(), project)
+}
+
+func (docat *Docat) ClaimContext(ctx context.Context, project string) (ProjectClaim, error) {
 	apiUrl, err := url.JoinPath(docat.Host, "api", project, "claim")
 	if err != nil {
-		return ProjectClaim{}, fmt.Errorf("unable to claim project because cannot create an url for host: %s error: %s", docat.Host, err)
+		return ProjectClaim{}, fmt.Errorf("unable to claim project because cannot create an url for host: %s error: %w", docat.Host, err)
 	}
 
-	response, err := http.Get(apiUrl)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, apiUrl, nil)
 	if err != nil {
-		return ProjectClaim{}, fmt.Errorf("unable to claim project because request failed: %s", err)
+		return ProjectClaim{}, fmt.Errorf("unable to claim project because cannot create GET request: %w", err)
 	}
-	defer func() { _ = response.Body.Close() }()
 
-	bodyBytes, err := io.ReadAll(response.Body)
+	// NOTE: claiming is not retried, because a claimed project cannot be claimed again.
+	response, err := docat.client().Do(request)
 	if err != nil {
-		return ProjectClaim{}, fmt.Errorf("unable to claim project and read it's response (status code: %d", response.StatusCode)
+		return ProjectClaim{}, fmt.Errorf("unable to claim project because request failed: %w", err)
 	}
+	defer func() { _ = response.Body.Close() }()
 
 	if response.StatusCode != http.StatusCreated {
-		return ProjectClaim{}, fmt.Errorf("unable to claim project: (status code: %d) %s", response.StatusCode, string(bodyBytes))
+		return ProjectClaim{}, newAPIError("claim project", request, response)
+	}
+
+	bodyBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return ProjectClaim{}, fmt.Errorf("unable to claim project and read it's response (status code: %d)", response.StatusCode)
 	}
 
 	var claim ProjectClaim
@@ -133,166 +253,296 @@ func (docat *Docat) Claim(project string) (ProjectClaim, error) {
 }
 
 func (docat *Docat) Tag(project string, version string, tag string) error {
+	return docat.TagContext(context.Background(), project, version, tag)
+}
+
+func (docat *Docat) TagContext(ctx context.Context, project string, version string, tag string) error {
 	apiUrl, err := url.JoinPath(docat.Host, "api", project, version, "tags", tag)
 	if err != nil {
-		return fmt.Errorf("unable to tag documentation because cannot create an url for host: %s error: %s", docat.Host, err)
+		return fmt.Errorf("unable to tag documentation because cannot create an url for host: %s error: %w", docat.Host, err)
 	}
 
-	request, err := http.NewRequest(http.MethodPut, apiUrl, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, apiUrl, nil)
 	if err != nil {
-		return fmt.Errorf("unable to tag documentation because cannot create PUT request: %s", err)
+		return fmt.Errorf("unable to tag documentation because cannot create PUT request: %w", err)
 	}
 
-	client := &http.Client{}
-	response, err := client.Do(request)
+	response, err := docat.do(request)
 	if err != nil {
-		return fmt.Errorf("unable to tag documentation because request failed: %s", err)
+		return fmt.Errorf("unable to tag documentation because request failed: %w", err)
 	}
 	defer func() { _ = response.Body.Close() }()
 
 	if response.StatusCode != http.StatusCreated {
-		bodyBytes, err := io.ReadAll(response.Body)
-		if err != nil {
-			return fmt.Errorf("unable to tag documentation and read it's response (status code: %d", response.StatusCode)
-		}
-		return fmt.Errorf("unable to tag documentation: (status code: %d) %s", response.StatusCode, string(bodyBytes))
+		return newAPIError("tag documentation", request, response)
 	}
 
 	return nil
 }
 
 func (docat *Docat) PushIcon(project string, iconPath string) error {
-	file, err := os.Open(iconPath)
+	return docat.PushIconContext(context.Background(), project, iconPath)
+}
+
+func (docat *Docat) PushIconContext(ctx context.Context, project string, iconPath string) error {
+	apiUrl, err := url.JoinPath(docat.Host, "api", project, "icon")
 	if err != nil {
-		return fmt.Errorf("unable to upload icon because the path '%s' does not exist", iconPath)
+		return fmt.Errorf("unable to upload icon because creating an url for host: %s failed with error: %w", docat.Host, err)
 	}
-	defer func() { _ = file.Close() }()
 
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	part, err := writer.CreateFormFile("file", filepath.Base(file.Name()))
+	request, err := docat.newUploadRequest(ctx, apiUrl, iconPath)
 	if err != nil {
-		return fmt.Errorf("unable to upload icon because create form file for file '%s' failed", iconPath)
+		return fmt.Errorf("unable to upload icon because %w", err)
 	}
 
-	_, err = io.Copy(part, file)
+	if docat.ApiKey != "" {
+		request.Header.Add("Docat-Api-Key", docat.ApiKey)
+	}
+
+	response, err := docat.do(request)
+
 	if err != nil {
-		return fmt.Errorf("unable to upload icon because copy file content from file '%s' to request failed", iconPath)
+		return fmt.Errorf("unable to upload icon because the request failed: %w", err)
 	}
-	if err = writer.Close(); err != nil {
-		return fmt.Errorf("unable to upload icon because cannot close multipart writer: %s", err)
+
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode != http.StatusOK {
+		return newAPIError("upload icon", request, response)
 	}
 
-	apiUrl, err := url.JoinPath(docat.Host, "api", project, "icon")
+	return nil
+}
+
+func (docat *Docat) Rename(project string, newName string) error {
+	return docat.RenameContext(context.Background(), project, newName)
+}
+
+func (docat *Docat) RenameContext(ctx context.Context, project string, newName string) error {
+	apiUrl, err := url.JoinPath(docat.Host, "api", project, "rename", newName)
+
 	if err != nil {
-		return fmt.Errorf("unable to upload icon because creating an url for host: %s failed with error: %s", docat.Host, err)
+		return fmt.Errorf("unable to rename project because creating an url failed for host: %s error: %w", docat.Host, err)
 	}
 
-	request, err := http.NewRequest(http.MethodPost, apiUrl, bytes.NewReader(body.Bytes()))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, apiUrl, nil)
 	if err != nil {
-		return fmt.Errorf("unable to upload icon because creating the POST request failed: %s", err)
-	}
+		return fmt.Errorf("unable to rename project because creating PUT request failed: %w", err)
 
-	request.Header.Add("Content-Type", writer.FormDataContentType())
+	}
 
 	if docat.ApiKey != "" {
 		request.Header.Add("Docat-Api-Key", docat.ApiKey)
 	}
 
-	client := &http.Client{}
-	response, err := client.Do(request)
+	// NOTE: renaming is not retried, because the project does not exist under its old name anymore.
+	response, err := docat.client().Do(request)
 
 	if err != nil {
-		return fmt.Errorf("unable to upload icon because the request failed: %s", err)
+		return fmt.Errorf("unable to rename project because the request failed: %w", err)
 	}
 
 	defer func() { _ = response.Body.Close() }()
 
-	if response.StatusCode == http.StatusOK {
-		return nil
+	if response.StatusCode != http.StatusOK {
+		return newAPIError("rename project", request, response)
 	}
 
-	bodyBytes, err := io.ReadAll(response.Body)
+	return nil
+}
+
+func (docat *Docat) HideOrShowVersion(project string, version string, hide bool) error {
+	return docat.HideOrShowVersionContext(context.Background(), project, version, hide)
+}
+
+func (docat *Docat) HideOrShowVersionContext(ctx context.Context, project string, version string, hide bool) error {
+	var hideOrShow string
+	if hide {
+		hideOrShow = "hide"
+	} else {
+		hideOrShow = "show"
+	}
+
+	apiUrl, err := url.JoinPath(docat.Host, "api", project, version, hideOrShow)
+	if err != nil {
+		return fmt.Errorf("unable to %s version because creating an url failed for host: %s error: %w", hideOrShow, docat.Host, err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, apiUrl, nil)
+	if err != nil {
+		return fmt.Errorf("unable to %s version because creating POST request failed: %w", hideOrShow, err)
+	}
+	if docat.ApiKey != "" {
+		request.Header.Add("Docat-Api-Key", docat.ApiKey)
+	}
 
+	response, err := docat.do(request)
 	if err != nil {
-		return fmt.Errorf("unable to upload icon and read it's response (status code: %d", response.StatusCode)
+		return fmt.Errorf("unable to %s version because request failed: %w", hideOrShow, err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode != http.StatusOK {
+		return newAPIError(hideOrShow+" version", request, response)
 	}
 
-	return fmt.Errorf("unable to upload icon: (status code: %d) %s", response.StatusCode, string(bodyBytes))
+	return nil
 }
 
-func (docat *Docat) Rename(project string, newName string) error {
-	apiUrl, err := url.JoinPath(docat.Host, "api", project, "rename", newName)
+func (docat *Docat) ListProjects(includeHidden bool) ([]Project, error) {
+	return docat.ListProjectsContext(context.Background(), includeHidden)
+}
 
+func (docat *Docat) ListProjectsContext(ctx context.Context, includeHidden bool) ([]Project, error) {
+	apiUrl, err := url.JoinPath(docat.Host, "api", "projects")
 	if err != nil {
-		return fmt.Errorf("unable to rename project because creating an url failed for host: %s error: %s", docat.Host, err)
+		return nil, fmt.Errorf("unable to list projects because creating an url failed for host: %s error: %w", docat.Host, err)
+	}
+
+	var projects struct {
+		Projects []Project `json:"projects"`
 	}
+	if err = docat.getJSON(ctx, "list projects", apiUrl, includeHidden, &projects); err != nil {
+		return nil, err
+	}
+	return projects.Projects, nil
+}
+
+func (docat *Docat) GetProject(project string, includeHidden bool) (Project, error) {
+	return docat.GetProjectContext(context.Background(), project, includeHidden)
+}
 
-	request, err := http.NewRequest(http.MethodPut, apiUrl, nil)
+func (docat *Docat) GetProjectContext(ctx context.Context, project string, includeHidden bool) (Project, error) {
+	apiUrl, err := url.JoinPath(docat.Host, "api", project)
 	if err != nil {
-		return fmt.Errorf("unable to rename project because creating PUT request failed: %s", err)
+		return Project{}, fmt.Errorf("unable to get project because creating an url failed for host: %s error: %w", docat.Host, err)
+	}
+
+	var details Project
+	if err = docat.getJSON(ctx, "get project "+project, apiUrl, includeHidden, &details); err != nil {
+		return Project{}, err
+	}
+	if details.Name == "" {
+		details.Name = project
+	}
+	return details, nil
+}
+
+// Download stores the zip archive of the hosted documentation of the given version at destPath.
+func (docat *Docat) Download(project string, version string, destPath string) error {
+	return docat.DownloadContext(context.Background(), project, version, destPath)
+}
 
+func (docat *Docat) DownloadContext(ctx context.Context, project string, version string, destPath string) error {
+	apiUrl, err := url.JoinPath(docat.Host, "api", project, version, "download")
+	if err != nil {
+		return fmt.Errorf("unable to download documentation because creating an url failed for host: %s error: %w", docat.Host, err)
 	}
 
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, apiUrl, nil)
+	if err != nil {
+		return fmt.Errorf("unable to download documentation because creating GET request failed: %w", err)
+	}
 	if docat.ApiKey != "" {
 		request.Header.Add("Docat-Api-Key", docat.ApiKey)
 	}
 
-	client := &http.Client{}
-	response, err := client.Do(request)
-
+	response, err := docat.do(request)
 	if err != nil {
-		return fmt.Errorf("unable to rename project because the request failed: %s", err)
+		return fmt.Errorf("unable to download documentation because request failed: %w", err)
 	}
-
 	defer func() { _ = response.Body.Close() }()
 
-	if response.StatusCode == http.StatusOK {
-		return nil
+	if response.StatusCode != http.StatusOK {
+		return newAPIError("download documentation", request, response)
 	}
 
-	bodyBytes, err := io.ReadAll(response.Body)
+	file, err := os.Create(destPath)
 	if err != nil {
-		return fmt.Errorf("unable to rename project and read the response (status code: %d", response.StatusCode)
+		return fmt.Errorf("unable to download documentation because cannot create file '%s': %w", destPath, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	if _, err = io.Copy(file, response.Body); err != nil {
+		return fmt.Errorf("unable to download documentation because writing to '%s' failed: %w", destPath, err)
 	}
-	return fmt.Errorf("unable to rename project: (status code: %d) %s", response.StatusCode, string(bodyBytes))
+	return file.Close()
 }
 
-func (docat *Docat) HideOrShowVersion(project string, version string, hide bool) error {
-	var hideOrShow string
-	if hide {
-		hideOrShow = "hide"
-	} else {
-		hideOrShow = "show"
+// DownloadIcon stores the icon of the project in destDir and returns the path of the icon file.
+func (docat *Docat) DownloadIcon(project string, destDir string) (string, error) {
+	return docat.DownloadIconContext(context.Background(), project, destDir)
+}
+
+func (docat *Docat) DownloadIconContext(ctx context.Context, project string, destDir string) (string, error) {
+	iconUrl, err := url.JoinPath(docat.Host, "doc", project, "logo")
+	if err != nil {
+		return "", fmt.Errorf("unable to download icon because creating an url failed for host: %s error: %w", docat.Host, err)
 	}
 
-	apiUrl, err := url.JoinPath(docat.Host, "api", project, version, hideOrShow)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, iconUrl, nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to download icon because creating GET request failed: %w", err)
+	}
+
+	response, err := docat.do(request)
+	if err != nil {
+		return "", fmt.Errorf("unable to download icon because request failed: %w", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode != http.StatusOK {
+		return "", newAPIError("download icon", request, response)
+	}
+
+	iconPath := filepath.Join(destDir, "logo")
+	if extensions, err := mime.ExtensionsByType(response.Header.Get("Content-Type")); err == nil && len(extensions) > 0 {
+		iconPath += extensions[0]
+	}
+
+	file, err := os.Create(iconPath)
 	if err != nil {
-		return fmt.Errorf("unable to %s version because creating an url failed for host: %s error: %s", hideOrShow, docat.Host, err)
+		return "", fmt.Errorf("unable to download icon because cannot create file '%s': %w", iconPath, err)
 	}
+	defer func() { _ = file.Close() }()
+
+	if _, err = io.Copy(file, response.Body); err != nil {
+		return "", fmt.Errorf("unable to download icon because writing to '%s' failed: %w", iconPath, err)
+	}
+	return iconPath, file.Close()
+}
 
-	request, err := http.NewRequest(http.MethodPost, apiUrl, nil)
+func (docat *Docat) getJSON(ctx context.Context, operation string, apiUrl string, includeHidden bool, v any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, apiUrl, nil)
 	if err != nil {
-		return fmt.Errorf("unable to %s version because creating POST request failed: %s", hideOrShow, err)
+		return fmt.Errorf("unable to %s because cannot create GET request: %w", operation, err)
+	}
+	if includeHidden {
+		query := request.URL.Query()
+		query.Set("include_hidden", "true")
+		request.URL.RawQuery = query.Encode()
 	}
 	if docat.ApiKey != "" {
 		request.Header.Add("Docat-Api-Key", docat.ApiKey)
 	}
 
-	client := &http.Client{}
-	response, err := client.Do(request)
+	response, err := docat.do(request)
 	if err != nil {
-		return fmt.Errorf("unable to %s version because request failed: %s", hideOrShow, err)
+		return fmt.Errorf("unable to %s because request failed: %w", operation, err)
 	}
 	defer func() { _ = response.Body.Close() }()
 
 	if response.StatusCode != http.StatusOK {
-		bodyBytes, err := io.ReadAll(response.Body)
-		if err != nil {
-			return fmt.Errorf("unable to %s version and read it's response (status code: %d", hideOrShow, response.StatusCode)
-		}
-		return fmt.Errorf("unable to %s version: (status code: %d) %s", hideOrShow, response.StatusCode, string(bodyBytes))
+		return newAPIError(operation, request, response)
 	}
 
+	bodyBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("unable to %s and read it's response (status code: %d)", operation, response.StatusCode)
+	}
+
+	if err = json.Unmarshal(bodyBytes, v); err != nil {
+		return fmt.Errorf("unable to %s, because cannot unmarshal response from server: %s", operation, string(bodyBytes))
+	}
 	return nil
 }
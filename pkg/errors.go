@@ -0,0 +1,75 @@
+package docatl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrNotFound matches API errors for projects, versions or tags which do not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized matches API errors for missing or invalid api keys.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrConflict matches API errors for already existing projects, versions or tags.
+	ErrConflict = errors.New("conflict")
+	// ErrIntegrity matches artifacts which don't match their checksums.
+	ErrIntegrity = errors.New("integrity check failed")
+)
+
+// APIError is returned when docat responds with an unexpected status code.
+// Use errors.Is with ErrNotFound, ErrUnauthorized or ErrConflict to check for common failures.
+type APIError struct {
+	// Operation describes what failed, e.g. "upload documentation".
+	Operation  string
+	Method     string
+	Endpoint   string
+	StatusCode int
+	// Message is the message docat responded with.
+	Message string
+}
+
+func (err *APIError) Error() string {
+	return fmt.Sprintf("unable to %s: (status code: %d) %s", err.Operation, err.StatusCode, err.Message)
+}
+
+func (err *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return err.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return err.StatusCode == http.StatusUnauthorized || err.StatusCode == http.StatusForbidden
+	case ErrConflict:
+		return err.StatusCode == http.StatusConflict
+	default:
+		return false
+	}
+}
+
+func newAPIError(operation string, request *http.Request, response *http.Response) *APIError {
+	apiErr := &APIError{
+		Operation:  operation,
+		Method:     request.Method,
+		Endpoint:   request.URL.Redacted(),
+		StatusCode: response.StatusCode,
+	}
+
+	bodyBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		apiErr.Message = fmt.Sprintf("unable to read response: %s", err)
+		return apiErr
+	}
+
+	var body struct {
+		Message string `json:"message"`
+	}
+	if err = json.Unmarshal(bodyBytes, &body); err == nil && body.Message != "" {
+		apiErr.Message = body.Message
+	} else {
+		apiErr.Message = strings.TrimSpace(string(bodyBytes))
+	}
+	return apiErr
+}
@@ -0,0 +1,147 @@
+package docatl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const ignoreFileName = ".docatlignore"
+
+type ignorePattern struct {
+	segments []string
+	negate   bool
+	dirOnly  bool
+	// anchored patterns are matched against the whole path relative to the docs root,
+	// others against the name of the file or directory at any level.
+	anchored bool
+}
+
+// ignoreMatcher matches paths against gitignore-style patterns, where later patterns take precedence.
+type ignoreMatcher struct {
+	patterns []ignorePattern
+}
+
+func newIgnoreMatcher(patterns []string) (ignoreMatcher, error) {
+	var matcher ignoreMatcher
+	for _, line := range patterns {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var pattern ignorePattern
+		if strings.HasPrefix(line, "!") {
+			pattern.negate = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`)
+		if strings.HasSuffix(line, "/") {
+			pattern.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			pattern.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		pattern.segments = strings.Split(line, "/")
+		for _, segment := range pattern.segments {
+			if _, err := path.Match(segment, ""); err != nil {
+				return ignoreMatcher{}, fmt.Errorf("invalid pattern '%s': %w", line, err)
+			}
+		}
+		matcher.patterns = append(matcher.patterns, pattern)
+	}
+	return matcher, nil
+}
+
+// readIgnoreFile returns the patterns of the .docatlignore file in the docs root, if there is one.
+func readIgnoreFile(docsPath string) ([]string, error) {
+	file, err := os.Open(filepath.Join(docsPath, ignoreFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", ignoreFileName, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var patterns []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		patterns = append(patterns, scanner.Text())
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", ignoreFileName, err)
+	}
+	return patterns, nil
+}
+
+func (matcher ignoreMatcher) empty() bool {
+	return len(matcher.patterns) == 0
+}
+
+// matches reports whether the slash separated path relative to the docs root matches.
+func (matcher ignoreMatcher) matches(relPath string, isDir bool) bool {
+	matched := false
+	for _, pattern := range matcher.patterns {
+		if pattern.match(relPath, isDir) {
+			matched = !pattern.negate
+		}
+	}
+	return matched
+}
+
+// matchesWithParents reports whether the path or any of its parent directories match.
+func (matcher ignoreMatcher) matchesWithParents(relPath string) bool {
+	if matcher.matches(relPath, false) {
+		return true
+	}
+	for dir := path.Dir(relPath); dir != "."; dir = path.Dir(dir) {
+		if matcher.matches(dir, true) {
+			return true
+		}
+	}
+	return false
+}
+
+func (pattern ignorePattern) match(relPath string, isDir bool) bool {
+	if pattern.dirOnly && !isDir {
+		return false
+	}
+	if !pattern.anchored {
+		return matchSegments(pattern.segments, []string{path.Base(relPath)})
+	}
+	return matchSegments(pattern.segments, strings.Split(relPath, "/"))
+}
+
+// matchSegments matches path segments against pattern segments, where `**` matches any number of segments.
+func matchSegments(pattern []string, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if matched, _ := path.Match(pattern[0], segments[0]); !matched {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
@@ -0,0 +1,201 @@
+package docatl
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	util "github.com/docat-org/docatl/internal"
+)
+
+// lint severities
+const (
+	LintWarning = "warning"
+	LintError   = "error"
+)
+
+// LintRules configures the checks of Lint.
+type LintRules struct {
+	// Exclude and Include select the files to check, like the files to archive in BuildOptions.
+	Exclude []string
+	Include []string
+	// MaxFileSize is the size in bytes above which a file is reported, 0 disables the check.
+	MaxFileSize int64
+	// MaxTotalSize is the size in bytes above which the documentation is reported, 0 disables the check.
+	MaxTotalSize int64
+	// DisallowedExtensions are file extensions which must not be part of the documentation, e.g. `.exe`.
+	DisallowedExtensions []string
+}
+
+// LintIssue is a problem found in a documentation directory.
+// The path is relative to the documentation root and empty for issues of the whole documentation.
+type LintIssue struct {
+	Severity string `json:"severity" yaml:"severity"`
+	Path     string `json:"path,omitempty" yaml:"path,omitempty"`
+	Message  string `json:"message" yaml:"message"`
+}
+
+func (issue LintIssue) String() string {
+	if issue.Path == "" {
+		return fmt.Sprintf("%s: %s", issue.Severity, issue.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", issue.Severity, issue.Path, issue.Message)
+}
+
+// absolutePathPattern matches links to absolute paths in HTML and CSS, which break because docat
+// serves the documentation below /doc/<project>/<version>/. Protocol-relative links (//host/...) are not matched.
+var absolutePathPattern = regexp.MustCompile(`(?i)(?:\b(?:href|src)\s*=\s*["']?|url\(\s*["']?)/(?:[^/]|$)`)
+
+// Lint checks the documentation directory for problems which result in broken documentation on docat:
+// a missing index.html in the root, empty directories, files and documentation exceeding the size limits,
+// disallowed file types, links to absolute paths and symlinks pointing outside of the documentation.
+// Problems which certainly break the documentation are reported as errors, all others as warnings.
+func Lint(docsPath string, rules LintRules) ([]LintIssue, error) {
+	if !util.IsDirectory(docsPath) {
+		return nil, fmt.Errorf("the given documentation path must be a directory")
+	}
+	docsPath = util.ResolvePath(docsPath)
+
+	root, err := filepath.EvalSymlinks(docsPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve documentation path '%s': %w", docsPath, err)
+	}
+
+	ignorePatterns, err := readIgnoreFile(docsPath)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := newIgnoreMatcher(append(ignorePatterns, rules.Exclude...))
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+	include, err := newIgnoreMatcher(rules.Include)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include pattern: %w", err)
+	}
+
+	var issues []LintIssue
+	report := func(severity string, path string, format string, v ...any) {
+		issues = append(issues, LintIssue{Severity: severity, Path: path, Message: fmt.Sprintf(format, v...)})
+	}
+
+	var dirs []string
+	nonEmpty := map[string]bool{}
+	hasIndex := false
+	var totalSize int64
+
+	err = filepath.WalkDir(docsPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == docsPath {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(docsPath, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(relPath)
+
+		if d.IsDir() {
+			if exclude.matches(name, true) {
+				return filepath.SkipDir
+			}
+			dirs = append(dirs, name)
+			return nil
+		}
+
+		if name == ignoreFileName || exclude.matches(name, false) {
+			return nil
+		}
+		if !include.empty() && !include.matchesWithParents(name) {
+			return nil
+		}
+		for dir := filepath.Dir(relPath); dir != "."; dir = filepath.Dir(dir) {
+			nonEmpty[filepath.ToSlash(dir)] = true
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 {
+			target, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				report(LintError, name, "symlink can't be resolved")
+				return nil
+			}
+			if rel, err := filepath.Rel(root, target); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				report(LintError, name, "symlink points outside of the documentation to '%s'", target)
+				return nil
+			}
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			// symlinked directories are not archived
+			return nil
+		}
+
+		if name == "index.html" {
+			hasIndex = true
+		}
+		if ext := strings.ToLower(filepath.Ext(name)); ext != "" && slices.Contains(rules.DisallowedExtensions, ext) {
+			report(LintError, name, "file type %s is not allowed", ext)
+		}
+		if rules.MaxFileSize > 0 && info.Size() > rules.MaxFileSize {
+			report(LintWarning, name, "file size of %d bytes exceeds the limit of %d bytes", info.Size(), rules.MaxFileSize)
+		}
+		totalSize += info.Size()
+
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".html", ".htm", ".css":
+			links, err := absolutePathLinks(path)
+			if err != nil {
+				return err
+			}
+			if links > 0 {
+				report(LintWarning, name, "links to absolute paths break when served by docat, use relative paths instead (found %d)", links)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to lint documentation in '%s': %w", docsPath, err)
+	}
+
+	if !hasIndex {
+		report(LintError, "", "no index.html in the root of the documentation, docat would show an empty page")
+	}
+	if rules.MaxTotalSize > 0 && totalSize > rules.MaxTotalSize {
+		report(LintError, "", "documentation size of %d bytes exceeds the limit of %d bytes", totalSize, rules.MaxTotalSize)
+	}
+
+	// only the topmost of nested empty directories is reported
+	var reported []string
+	for _, dir := range dirs {
+		if nonEmpty[dir] || slices.ContainsFunc(reported, func(parent string) bool { return strings.HasPrefix(dir, parent+"/") }) {
+			continue
+		}
+		reported = append(reported, dir)
+		report(LintWarning, dir, "directory contains no files")
+	}
+
+	slices.SortStableFunc(issues, func(a, b LintIssue) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return issues, nil
+}
+
+// absolutePathLinks returns the number of links to absolute paths in the HTML or CSS file.
+func absolutePathLinks(path string) (int, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read '%s': %w", path, err)
+	}
+	return len(absolutePathPattern.FindAllIndex(contents, -1)), nil
+}
@@ -0,0 +1,69 @@
+package docatl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest lists documentation to push in one go, e.g.:
+//
+//	entries:
+//	  - docs: ./service-a/docs
+//	    project: service-a
+//	    version: 1.0.0
+//	    tags: [latest]
+//	    icon: ./service-a/logo.png
+type Manifest struct {
+	Entries []ManifestEntry `yaml:"entries"`
+}
+
+type ManifestEntry struct {
+	// Docs is a documentation directory or artifact, relative to the manifest.
+	Docs string `yaml:"docs"`
+	// Project and Version may be omitted for artifacts containing them in their metadata.
+	Project string   `yaml:"project,omitempty"`
+	Version string   `yaml:"version,omitempty"`
+	Tags    []string `yaml:"tags,omitempty"`
+	// Icon is the path of an icon to push for the project, relative to the manifest.
+	Icon   string `yaml:"icon,omitempty"`
+	Hidden bool   `yaml:"hidden,omitempty"`
+}
+
+// ReadManifest reads the manifest and resolves the paths of its entries relative to the manifest.
+func ReadManifest(manifestPath string) (Manifest, error) {
+	doc, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("unable to read manifest '%s': %w", manifestPath, err)
+	}
+
+	var manifest Manifest
+	if err = yaml.Unmarshal(doc, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("unable to parse manifest '%s': %w", manifestPath, err)
+	}
+	if len(manifest.Entries) == 0 {
+		return Manifest{}, fmt.Errorf("manifest '%s' has no entries", manifestPath)
+	}
+
+	baseDir := filepath.Dir(manifestPath)
+	for i, entry := range manifest.Entries {
+		if entry.Docs == "" {
+			return Manifest{}, fmt.Errorf("entry %d of manifest '%s' has no docs", i+1, manifestPath)
+		}
+		manifest.Entries[i].Docs = resolveManifestPath(baseDir, entry.Docs)
+		if entry.Icon != "" {
+			manifest.Entries[i].Icon = resolveManifestPath(baseDir, entry.Icon)
+		}
+	}
+
+	return manifest, nil
+}
+
+func resolveManifestPath(baseDir string, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
@@ -0,0 +1,60 @@
+package docatl
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// PrunePolicy decides which versions of a project are kept.
+// A version is deleted when none of the keep rules match and,
+// if OlderThan is set, it was uploaded longer than OlderThan ago.
+type PrunePolicy struct {
+	// Keep is the number of newest versions (by semantic versioning) to keep.
+	Keep int
+	// KeepTagged keeps every version which has at least one tag.
+	KeepTagged bool
+	// KeepPattern keeps every version whose name matches.
+	KeepPattern *regexp.Regexp
+	// OlderThan only deletes versions uploaded longer ago than this duration.
+	OlderThan time.Duration
+}
+
+type PruneDecision struct {
+	Version ProjectVersion
+	Delete  bool
+	Reason  string
+}
+
+// Plan returns a decision for each of the given versions, newest version first.
+func (policy PrunePolicy) Plan(versions []ProjectVersion, now time.Time) []PruneDecision {
+	sorted := slices.Clone(versions)
+	slices.SortStableFunc(sorted, func(a, b ProjectVersion) int {
+		return CompareVersions(b.Name, a.Name)
+	})
+
+	decisions := make([]PruneDecision, 0, len(sorted))
+	for i, version := range sorted {
+		decision := PruneDecision{Version: version}
+		switch {
+		case i < policy.Keep:
+			decision.Reason = fmt.Sprintf("one of the %d newest versions", policy.Keep)
+		case policy.KeepTagged && len(version.Tags) > 0:
+			decision.Reason = fmt.Sprintf("tagged as %s", strings.Join(version.Tags, ", "))
+		case policy.KeepPattern != nil && policy.KeepPattern.MatchString(version.Name):
+			decision.Reason = fmt.Sprintf("matches %s", policy.KeepPattern)
+		case policy.OlderThan > 0 && version.Timestamp.IsZero():
+			decision.Reason = "upload time unknown"
+		case policy.OlderThan > 0 && now.Sub(version.Timestamp) <= policy.OlderThan:
+			decision.Reason = fmt.Sprintf("not older than %s", policy.OlderThan)
+		default:
+			decision.Delete = true
+			decision.Reason = "outside of retention policy"
+		}
+		decisions = append(decisions, decision)
+	}
+
+	return decisions
+}
@@ -0,0 +1,154 @@
+package docatl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how requests failing with transient errors are retried.
+// Transient errors are network errors and the status codes 429, 502, 503 and 504.
+// Requests are only retried if their body can be replayed. Requests which are not idempotent, like uploads,
+// are only retried if the connection could not be established, or on the status code 503,
+// and 429 with a Retry-After header, as docat may have processed them already otherwise.
+type RetryPolicy struct {
+	// Retries is the number of retries after the first attempt, requests are not retried if 0.
+	Retries int
+	// MinWait is the wait before the first retry, it doubles with every further retry.
+	MinWait time.Duration
+	// MaxWait caps the wait between retries, including waits requested with Retry-After.
+	MaxWait time.Duration
+	// Jitter is the fraction (0 to 1) of each wait which is randomized.
+	Jitter float64
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	Retries: 3,
+	MinWait: time.Second,
+	MaxWait: 30 * time.Second,
+	Jitter:  0.5,
+}
+
+func (docat *Docat) client() *http.Client {
+	if docat.Client == nil {
+		return http.DefaultClient
+	}
+	return docat.Client
+}
+
+// do sends the request and retries it according to the retry policy.
+func (docat *Docat) do(request *http.Request) (*http.Response, error) {
+	policy := docat.Retry
+	if policy.Retries <= 0 || (request.Body != nil && request.Body != http.NoBody && request.GetBody == nil) {
+		return docat.client().Do(request)
+	}
+
+	for attempt := 1; ; attempt++ {
+		response, err := docat.client().Do(request)
+		if attempt > policy.Retries || !isRetryable(request, response, err) || request.Context().Err() != nil {
+			return response, err
+		}
+
+		wait := policy.wait(attempt, response)
+		reason := ""
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = fmt.Sprintf("status code: %d", response.StatusCode)
+			_, _ = io.Copy(io.Discard, response.Body)
+			_ = response.Body.Close()
+		}
+		log.Printf("%s %s failed (%s), retrying in %s (retry %d of %d)", request.Method, request.URL.Redacted(), reason, wait.Round(time.Millisecond), attempt, policy.Retries)
+
+		select {
+		case <-request.Context().Done():
+			return nil, request.Context().Err()
+		case <-time.After(wait):
+		}
+
+		if request.GetBody != nil {
+			if request.Body, err = request.GetBody(); err != nil {
+				return nil, fmt.Errorf("cannot replay request body: %w", err)
+			}
+		}
+	}
+}
+
+// isRetryable returns whether the failed request can be sent again.
+// Requests which are not idempotent may have been processed by docat already, e.g. when a proxy
+// timed out waiting for docat with 504, so they are only retried if they were certainly refused.
+func isRetryable(request *http.Request, response *http.Response, err error) bool {
+	if request.Method != http.MethodPost && request.Method != http.MethodPatch {
+		return isTransient(response, err)
+	}
+
+	if err != nil {
+		var opErr *net.OpError
+		return errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+	switch response.StatusCode {
+	case http.StatusServiceUnavailable:
+		return true
+	case http.StatusTooManyRequests:
+		_, ok := parseRetryAfter(response)
+		return ok
+	default:
+		return false
+	}
+}
+
+func isTransient(response *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch response.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// wait returns how long to wait before the given retry.
+func (policy RetryPolicy) wait(retry int, response *http.Response) time.Duration {
+	wait := policy.MinWait << (retry - 1)
+	if wait <= 0 || (policy.MaxWait > 0 && wait > policy.MaxWait) {
+		wait = policy.MaxWait
+	}
+	if policy.Jitter > 0 {
+		wait -= time.Duration(rand.Float64() * min(policy.Jitter, 1) * float64(wait))
+	}
+
+	if retryAfter, ok := parseRetryAfter(response); ok {
+		wait = retryAfter
+		if policy.MaxWait > 0 && wait > policy.MaxWait {
+			wait = policy.MaxWait
+		}
+	}
+	return wait
+}
+
+func parseRetryAfter(response *http.Response) (time.Duration, bool) {
+	if response == nil {
+		return 0, false
+	}
+
+	value := response.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
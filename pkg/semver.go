@@ -0,0 +1,93 @@
+package docatl
+
+import (
+	"cmp"
+	"strconv"
+	"strings"
+)
+
+type semver struct {
+	core       [3]int
+	prerelease string
+}
+
+// parseSemver parses versions like `1.2.3`, `v1.2` or `1.2.3-rc.1+build`.
+func parseSemver(version string) (semver, bool) {
+	version = strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	version, _, _ = strings.Cut(version, "+")
+	core, prerelease, _ := strings.Cut(version, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return semver{}, false
+	}
+
+	var parsed semver
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return semver{}, false
+		}
+		parsed.core[i] = number
+	}
+	parsed.prerelease = prerelease
+	return parsed, true
+}
+
+// CompareVersions compares two version names by semantic versioning and returns
+// -1, 0 or +1. Version names which are not semantic versions sort before all
+// semantic versions and are compared lexically among each other.
+func CompareVersions(a string, b string) int {
+	semverA, okA := parseSemver(a)
+	semverB, okB := parseSemver(b)
+
+	switch {
+	case !okA && !okB:
+		return strings.Compare(a, b)
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+
+	for i := range semverA.core {
+		if result := cmp.Compare(semverA.core[i], semverB.core[i]); result != 0 {
+			return result
+		}
+	}
+
+	return comparePrerelease(semverA.prerelease, semverB.prerelease)
+}
+
+func comparePrerelease(a string, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		numberA, errA := strconv.Atoi(partsA[i])
+		numberB, errB := strconv.Atoi(partsB[i])
+
+		var result int
+		switch {
+		case errA == nil && errB == nil:
+			result = cmp.Compare(numberA, numberB)
+		case errA == nil:
+			result = -1
+		case errB == nil:
+			result = 1
+		default:
+			result = strings.Compare(partsA[i], partsB[i])
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return cmp.Compare(len(partsA), len(partsB))
+}
@@ -0,0 +1,111 @@
+package docatl
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// signatureFileName is the archive entry holding the ed25519 signature of the metadata.
+// As the metadata holds the checksums of all files, the signature covers the whole artifact.
+const signatureFileName = ".docatl.sig"
+
+const (
+	privateKeyPEMType = "PRIVATE KEY"
+	publicKeyPEMType  = "PUBLIC KEY"
+)
+
+// GenerateSigningKeys generates an ed25519 key pair for signing artifacts and writes the private key
+// as PEM encoded PKCS #8 and the public key as PEM encoded PKIX to the given paths.
+func GenerateSigningKeys(privateKeyPath string, publicKeyPath string) error {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return fmt.Errorf("unable to generate signing key: %w", err)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return fmt.Errorf("unable to marshal private key: %w", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return fmt.Errorf("unable to marshal public key: %w", err)
+	}
+
+	err = writePrivateFile(privateKeyPath, pem.EncodeToMemory(&pem.Block{Type: privateKeyPEMType, Bytes: privateDER}))
+	if err != nil {
+		return fmt.Errorf("unable to write private key to '%s': %w", privateKeyPath, err)
+	}
+	err = os.WriteFile(publicKeyPath, pem.EncodeToMemory(&pem.Block{Type: publicKeyPEMType, Bytes: publicDER}), 0644)
+	if err != nil {
+		return fmt.Errorf("unable to write public key to '%s': %w", publicKeyPath, err)
+	}
+	return nil
+}
+
+// ReadSigningKey reads a PEM encoded PKCS #8 ed25519 private key.
+func ReadSigningKey(path string) (ed25519.PrivateKey, error) {
+	der, err := readPEM(path, privateKeyPEMType)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse private key '%s': %w", path, err)
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unable to use private key '%s' because it is not an ed25519 key", path)
+	}
+	return privateKey, nil
+}
+
+// ReadPublicKey reads a PEM encoded PKIX ed25519 public key.
+func ReadPublicKey(path string) (ed25519.PublicKey, error) {
+	der, err := readPEM(path, publicKeyPEMType)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse public key '%s': %w", path, err)
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unable to use public key '%s' because it is not an ed25519 key", path)
+	}
+	return publicKey, nil
+}
+
+func readPEM(path string, blockType string) ([]byte, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read key '%s': %w", path, err)
+	}
+	block, _ := pem.Decode(contents)
+	if block == nil || block.Type != blockType {
+		return nil, fmt.Errorf("unable to read key '%s' because it is not a PEM encoded %s", path, strings.ToLower(blockType))
+	}
+	return block.Bytes, nil
+}
+
+func signMetadata(key ed25519.PrivateKey, metadata []byte) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, metadata)) + "\n")
+}
+
+func verifyMetadataSignature(key ed25519.PublicKey, metadata []byte, signature []byte) error {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return errors.New("the signature is not valid base64")
+	}
+	if !ed25519.Verify(key, metadata, decoded) {
+		return errors.New("the signature doesn't match the public key")
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package docatl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// ProgressFunc is called while a file is uploaded with the bytes of the file sent so far and its size.
+// It is called with 0 bytes sent whenever an upload (re)starts.
+type ProgressFunc func(sent int64, total int64)
+
+// uploadBody streams a file from disk as multipart form, without reading it into memory.
+type uploadBody struct {
+	path     string
+	size     int64
+	boundary string
+	progress ProgressFunc
+}
+
+// newUploadRequest creates a POST request uploading the file as multipart form.
+// The request has a known content length and its body can be replayed for retries.
+func (docat *Docat) newUploadRequest(ctx context.Context, apiUrl string, path string) (*http.Request, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("it isn't accessible locally at '%s': %w", path, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("'%s' is a directory", path)
+	}
+
+	body := &uploadBody{
+		path:     path,
+		size:     info.Size(),
+		boundary: multipart.NewWriter(io.Discard).Boundary(),
+		progress: docat.Progress,
+	}
+
+	length, err := body.contentLength()
+	if err != nil {
+		return nil, fmt.Errorf("cannot create form file for file '%s': %w", path, err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, apiUrl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create POST request: %w", err)
+	}
+	if request.Body, err = body.open(); err != nil {
+		return nil, fmt.Errorf("it isn't accessible locally at '%s': %w", path, err)
+	}
+	request.GetBody = body.open
+	request.ContentLength = length
+	request.Header.Add("Content-Type", body.contentType())
+
+	return request, nil
+}
+
+func (body *uploadBody) contentType() string {
+	writer := multipart.NewWriter(io.Discard)
+	_ = writer.SetBoundary(body.boundary)
+	return writer.FormDataContentType()
+}
+
+// contentLength returns the size of the multipart form, which is the size of the file and the form around it.
+func (body *uploadBody) contentLength() (int64, error) {
+	counter := &countingWriter{}
+	if err := body.writeForm(counter, http.NoBody); err != nil {
+		return 0, err
+	}
+	return counter.count + body.size, nil
+}
+
+// open opens the file and returns the multipart form, which is written through a pipe while it is read.
+func (body *uploadBody) open() (io.ReadCloser, error) {
+	file, err := os.Open(body.path)
+	if err != nil {
+		return nil, err
+	}
+
+	content := io.Reader(file)
+	if body.progress != nil {
+		body.progress(0, body.size)
+		content = &progressReader{reader: file, total: body.size, progress: body.progress}
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		defer func() { _ = file.Close() }()
+		_ = writer.CloseWithError(body.writeForm(writer, content))
+	}()
+	return reader, nil
+}
+
+func (body *uploadBody) writeForm(w io.Writer, content io.Reader) error {
+	writer := multipart.NewWriter(w)
+	if err := writer.SetBoundary(body.boundary); err != nil {
+		return err
+	}
+	part, err := writer.CreateFormFile("file", filepath.Base(body.path))
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(part, content); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+type countingWriter struct {
+	count int64
+}
+
+func (writer *countingWriter) Write(p []byte) (int, error) {
+	writer.count += int64(len(p))
+	return len(p), nil
+}
+
+type progressReader struct {
+	reader   io.Reader
+	sent     int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (reader *progressReader) Read(p []byte) (int, error) {
+	n, err := reader.reader.Read(p)
+	if n > 0 {
+		reader.sent += int64(n)
+		reader.progress(reader.sent, reader.total)
+	}
+	return n, err
+}
@@ -0,0 +1,237 @@
+package docatl
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/klauspost/compress/zip"
+	"gopkg.in/yaml.v2"
+)
+
+// ChecksumFileSuffix is appended to the path of an artifact for the checksum file holding its digest,
+// in the format of sha256sum, so it can be checked with `sha256sum -c` as well.
+const ChecksumFileSuffix = ".sha256"
+
+const checksumPrefix = "sha256:"
+
+// Verification is the outcome of verifying an artifact.
+type Verification struct {
+	// Digest is the SHA-256 digest of the artifact.
+	Digest string `json:"digest" yaml:"digest"`
+	// DigestVerified is set if the digest matches the checksum file next to the artifact.
+	DigestVerified bool `json:"digest-verified" yaml:"digest-verified"`
+	// FilesVerified is the number of files which match their checksums in the metadata.
+	FilesVerified int `json:"files-verified" yaml:"files-verified"`
+	// Signed is set if the artifact contains a signature.
+	Signed bool `json:"signed" yaml:"signed"`
+	// SignatureVerified is set if the signature matches the public key.
+	SignatureVerified bool `json:"signature-verified" yaml:"signature-verified"`
+}
+
+// Verified reports whether any checksum or signature was available to verify the artifact.
+func (verification Verification) Verified() bool {
+	return verification.DigestVerified || verification.FilesVerified > 0 || verification.SignatureVerified
+}
+
+// VerifyArtifact verifies the artifact against the digest of its checksum file and
+// the checksums of its files in the metadata, as far as they are present.
+// If a public key is given, the signature of signed artifacts is verified as well,
+// unsigned artifacts are reported with Signed being false.
+// Mismatches are reported with an error matching ErrIntegrity.
+func VerifyArtifact(artifactPath string, publicKey ed25519.PublicKey) (Verification, error) {
+	var verification Verification
+
+	digest, err := fileDigest(artifactPath)
+	if err != nil {
+		return verification, err
+	}
+	verification.Digest = checksumPrefix + hex.EncodeToString(digest)
+
+	expected, err := readChecksumFile(artifactPath)
+	if err != nil {
+		return verification, err
+	}
+	if expected != nil {
+		if !bytes.Equal(digest, expected) {
+			return verification, fmt.Errorf("unable to verify artifact '%s' because its digest %x doesn't match %x of its checksum file: %w", artifactPath, digest, expected, ErrIntegrity)
+		}
+		verification.DigestVerified = true
+	}
+
+	zr, err := openArtifact(artifactPath)
+	if err != nil {
+		return verification, err
+	}
+	defer func() { _ = zr.Close() }()
+
+	// a second metadata file could otherwise be read instead of the verified one
+	names := map[string]bool{}
+	for _, f := range zr.File {
+		if names[f.Name] {
+			return verification, fmt.Errorf("unable to verify artifact '%s' because it contains '%s' twice: %w", artifactPath, f.Name, ErrIntegrity)
+		}
+		names[f.Name] = true
+	}
+
+	metadata, err := readArchiveFile(artifactPath, zr, metadataFileName)
+	if err != nil {
+		return verification, err
+	}
+	signature, err := readArchiveFile(artifactPath, zr, signatureFileName)
+	if err != nil {
+		return verification, err
+	}
+
+	var meta BuildMetadata
+	if err = yaml.Unmarshal(metadata, &meta); err != nil {
+		return verification, fmt.Errorf("unable to read metadata of artifact '%s' as YAML: %w", artifactPath, err)
+	}
+
+	verification.Signed = signature != nil
+	if publicKey != nil && verification.Signed {
+		// the signature only covers the files through their checksums in the metadata
+		if len(meta.Checksums) == 0 {
+			return verification, fmt.Errorf("unable to verify artifact '%s' because its signed metadata has no checksums: %w", artifactPath, ErrIntegrity)
+		}
+		if err = verifyMetadataSignature(publicKey, metadata, signature); err != nil {
+			return verification, fmt.Errorf("unable to verify artifact '%s' because %w: %w", artifactPath, err, ErrIntegrity)
+		}
+		verification.SignatureVerified = true
+	}
+
+	if len(meta.Checksums) > 0 {
+		if verification.FilesVerified, err = verifyFiles(artifactPath, zr, meta.Checksums); err != nil {
+			return verification, err
+		}
+	}
+
+	return verification, nil
+}
+
+// readArchiveFile returns the contents of the named file in the artifact, or nil if there is none.
+func readArchiveFile(artifactPath string, zr *zip.ReadCloser, name string) ([]byte, error) {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+
+		r, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read '%s' from artifact '%s': %w", name, artifactPath, err)
+		}
+		defer func() { _ = r.Close() }()
+
+		contents, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read '%s' from artifact '%s': %w: %w", name, artifactPath, ErrIntegrity, err)
+		}
+		return contents, nil
+	}
+	return nil, nil
+}
+
+// verifyFiles checks the files of the artifact against the checksums of the metadata
+// and returns the number of verified files.
+func verifyFiles(artifactPath string, zr *zip.ReadCloser, checksums map[string]string) (int, error) {
+	verified := map[string]bool{}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || f.Name == metadataFileName || f.Name == signatureFileName {
+			continue
+		}
+
+		expected, ok := checksums[f.Name]
+		if !ok {
+			return len(verified), fmt.Errorf("unable to verify artifact '%s' because file '%s' has no checksum in its metadata: %w", artifactPath, f.Name, ErrIntegrity)
+		}
+
+		digest, err := zipFileDigest(f)
+		if err != nil {
+			return len(verified), fmt.Errorf("unable to verify artifact '%s' because file '%s' can't be read: %w: %w", artifactPath, f.Name, ErrIntegrity, err)
+		}
+		if checksumPrefix+hex.EncodeToString(digest) != expected {
+			return len(verified), fmt.Errorf("unable to verify artifact '%s' because file '%s' doesn't match its checksum: %w", artifactPath, f.Name, ErrIntegrity)
+		}
+		verified[f.Name] = true
+	}
+
+	if len(verified) < len(checksums) {
+		var missing []string
+		for name := range checksums {
+			if !verified[name] {
+				missing = append(missing, name)
+			}
+		}
+		slices.Sort(missing)
+		return len(verified), fmt.Errorf("unable to verify artifact '%s' because files are missing: %s: %w", artifactPath, strings.Join(missing, ", "), ErrIntegrity)
+	}
+
+	return len(verified), nil
+}
+
+func zipFileDigest(f *zip.File) ([]byte, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+
+	digest := sha256.New()
+	if _, err = io.Copy(digest, r); err != nil {
+		return nil, err
+	}
+	return digest.Sum(nil), nil
+}
+
+func fileDigest(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open '%s': %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	digest := sha256.New()
+	if _, err = io.Copy(digest, file); err != nil {
+		return nil, fmt.Errorf("unable to read '%s': %w", path, err)
+	}
+	return digest.Sum(nil), nil
+}
+
+func writeChecksumFile(artifactPath string, digest []byte) error {
+	line := fmt.Sprintf("%x  %s\n", digest, filepath.Base(artifactPath))
+	if err := os.WriteFile(artifactPath+ChecksumFileSuffix, []byte(line), 0644); err != nil {
+		return fmt.Errorf("unable to write checksum file for '%s': %w", artifactPath, err)
+	}
+	return nil
+}
+
+// readChecksumFile returns the digest of the checksum file next to the artifact, or nil if there is none.
+func readChecksumFile(artifactPath string) ([]byte, error) {
+	checksumPath := artifactPath + ChecksumFileSuffix
+	contents, err := os.ReadFile(checksumPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read checksum file '%s': %w", checksumPath, err)
+	}
+
+	fields := strings.Fields(string(contents))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("unable to read checksum file '%s' because it is empty", checksumPath)
+	}
+	digest, err := hex.DecodeString(fields[0])
+	if err != nil || len(digest) != sha256.Size {
+		return nil, fmt.Errorf("unable to read checksum file '%s' because '%s' is not a SHA-256 digest", checksumPath, fields[0])
+	}
+	return digest, nil
+}
@@ -0,0 +1,73 @@
+package docatl
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	util "github.com/docat-org/docatl/internal"
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watch calls onChange whenever files within docsPath (recursively) changed and
+// no further change happened for the debounce duration. It blocks until ctx is done.
+// Changes of paths for which ignore returns true, like artifacts written into docsPath, are ignored.
+func Watch(ctx context.Context, docsPath string, debounce time.Duration, ignore func(path string) bool, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("unable to watch documentation directory: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	if err = watchRecursive(watcher, docsPath); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op == fsnotify.Chmod || (ignore != nil && ignore(event.Name)) {
+				continue
+			}
+			if event.Has(fsnotify.Create) && util.IsDirectory(event.Name) {
+				if err = watchRecursive(watcher, event.Name); err != nil {
+					return err
+				}
+			}
+			timer.Reset(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("unable to watch documentation directory: %w", err)
+		case <-timer.C:
+			onChange()
+		}
+	}
+}
+
+func watchRecursive(watcher *fsnotify.Watcher, path string) error {
+	return filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if err = watcher.Add(path); err != nil {
+			return fmt.Errorf("unable to watch directory '%s': %w", path, err)
+		}
+		return nil
+	})
+}